@@ -5,6 +5,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/twpayne/go-vfs"
 	"github.com/variantdev/mod/pkg/cmdsite"
 	"github.com/variantdev/mod/pkg/loginfra"
 	"github.com/variantdev/mod/pkg/variantmod"
@@ -15,7 +16,7 @@ import (
 )
 
 func New(log logr.Logger) *cobra.Command {
-	var newVariantMod func() (*variantmod.ModuleManager, error)
+	var newVariantMod func(opts ...variantmod.Option) (*variantmod.ModuleManager, error)
 
 	cmd := cobra.Command{
 		Use:        "mod",
@@ -34,20 +35,66 @@ func New(log logr.Logger) *cobra.Command {
 	{
 		file := cmd.PersistentFlags().StringP("file", "f", "variant.mod", "Configuration file to load")
 
-		newVariantMod = func() (*variantmod.ModuleManager, error) {
-			return variantmod.New(
+		newVariantMod = func(opts ...variantmod.Option) (*variantmod.ModuleManager, error) {
+			return variantmod.New(append([]variantmod.Option{
 				variantmod.File(*file),
 				variantmod.Logger(log),
 				variantmod.Commander(cmdsite.DefaultRunCommand),
-			)
+			}, opts...)...)
 		}
 	}
 
+	// argsFlags adds the repeatable --set and --values flags to the command, that override parameters.defaults of the module
+	argsFlags := func(c *cobra.Command) func() ([]variantmod.Option, error) {
+		var sets, valuesFiles []string
+		c.Flags().StringArrayVar(&sets, "set", nil, "Override a parameter in the form of KEY=VALUE. Use dots like image.tag=v1 to set a nested parameter. Can be specified multiple times")
+		c.Flags().StringArrayVar(&valuesFiles, "values", nil, "YAML file containing parameters to override the defaults. Can be specified multiple times")
+		return func() ([]variantmod.Option, error) {
+			if len(sets) == 0 && len(valuesFiles) == 0 {
+				return nil, nil
+			}
+			args, err := variantmod.LoadArguments(vfs.HostOSFS, valuesFiles, sets)
+			if err != nil {
+				return nil, err
+			}
+			return []variantmod.Option{variantmod.Arguments(args)}, nil
+		}
+	}
+
+	diff := func(output string, args []string, opts ...variantmod.Option) error {
+		mod, err := newVariantMod(opts...)
+		if err != nil {
+			return err
+		}
+		r, err := mod.Diff(args...)
+		if err != nil {
+			return err
+		}
+		switch output {
+		case "json":
+			return r.WriteJSON(os.Stdout)
+		case "", "diff":
+			return r.WriteDiff(os.Stdout)
+		default:
+			return fmt.Errorf("unsupported output format %q: must be either \"diff\" or \"json\"", output)
+		}
+	}
+
+	var dryRun bool
+	var buildOutput string
+	var buildArgs func() ([]variantmod.Option, error)
 	modbuild := &cobra.Command{
 		Use:  "build [STAGE]",
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mod, err := newVariantMod()
+			opts, err := buildArgs()
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				return diff(buildOutput, args, opts...)
+			}
+			mod, err := newVariantMod(opts...)
 			if err != nil {
 				return err
 			}
@@ -55,11 +102,35 @@ func New(log logr.Logger) *cobra.Command {
 			return err
 		},
 	}
+	buildArgs = argsFlags(modbuild)
+	modbuild.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes that `build` would make to files, without writing them")
+	modbuild.Flags().StringVarP(&buildOutput, "output", "o", "diff", "Output format of --dry-run. Either \"diff\" or \"json\"")
 
+	var diffOutput string
+	var diffArgs func() ([]variantmod.Option, error)
+	moddiff := &cobra.Command{
+		Use:  "diff [STAGE]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := diffArgs()
+			if err != nil {
+				return err
+			}
+			return diff(diffOutput, args, opts...)
+		},
+	}
+	diffArgs = argsFlags(moddiff)
+	moddiff.Flags().StringVarP(&diffOutput, "output", "o", "diff", "Output format. Either \"diff\" or \"json\"")
+
+	var execArgs func() ([]variantmod.Option, error)
 	modexec := &cobra.Command{
 		Use: "exec",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			man, err := newVariantMod()
+			opts, err := execArgs()
+			if err != nil {
+				return err
+			}
+			man, err := newVariantMod(opts...)
 			if err != nil {
 				return err
 			}
@@ -70,6 +141,7 @@ func New(log logr.Logger) *cobra.Command {
 			return sh.RunCommand(args[0], args[1:], os.Stdout, os.Stderr)
 		},
 	}
+	execArgs = argsFlags(modexec)
 
 	modlistdepver := &cobra.Command{
 		Use:  "list-dependency-versions DEPENDENCY_NAME",
@@ -83,18 +155,116 @@ func New(log logr.Logger) *cobra.Command {
 		},
 	}
 
-	up := func(branch, title, body, base string, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle bool, args []string) error {
+	var outdatedOutput string
+	modoutdated := &cobra.Command{
+		Use:  "outdated",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			man, err := newVariantMod()
+			if err != nil {
+				return err
+			}
+			deps, err := man.Outdated()
+			if err != nil {
+				return err
+			}
+			switch outdatedOutput {
+			case "json":
+				err = variantmod.WriteOutdatedJSON(os.Stdout, deps)
+			case "", "table":
+				err = variantmod.WriteOutdatedTable(os.Stdout, deps)
+			default:
+				return fmt.Errorf("unsupported output format %q: must be either \"table\" or \"json\"", outdatedOutput)
+			}
+			if err != nil {
+				return err
+			}
+			var outdated []string
+			for _, d := range deps {
+				if d.Outdated && !d.Pinned {
+					outdated = append(outdated, d.Name)
+				}
+			}
+			if len(outdated) > 0 {
+				return fmt.Errorf("outdated dependencies found: %s", strings.Join(outdated, ", "))
+			}
+			return nil
+		},
+	}
+	modoutdated.Flags().StringVarP(&outdatedOutput, "output", "o", "table", "Output format. Either \"table\" or \"json\"")
+
+	modpin := &cobra.Command{
+		Use:  "pin DEPENDENCY_NAME=VERSION...",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			man, err := newVariantMod()
+			if err != nil {
+				return err
+			}
+			for _, a := range args {
+				kv := strings.SplitN(a, "=", 2)
+				if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+					return fmt.Errorf("invalid argument %q: must be in the form of DEPENDENCY_NAME=VERSION", a)
+				}
+				if err := man.Pin(kv[0], kv[1]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	modunpin := &cobra.Command{
+		Use:  "unpin DEPENDENCY_NAME...",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			man, err := newVariantMod()
+			if err != nil {
+				return err
+			}
+			for _, a := range args {
+				if err := man.Unpin(a); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	var validateArgs func() ([]variantmod.Option, error)
+	modvalidate := &cobra.Command{
+		Use:  "validate",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := validateArgs()
+			if err != nil {
+				return err
+			}
+			man, err := newVariantMod(opts...)
+			if err != nil {
+				return err
+			}
+			return man.Validate()
+		},
+	}
+	validateArgs = argsFlags(modvalidate)
+
+	up := func(branch, title, body, base string, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle bool, only []string, args []string, opts ...variantmod.Option) error {
 		if pr {
 			push = true
 		}
-		man, err := newVariantMod()
+		man, err := newVariantMod(opts...)
 		if err != nil {
 			return err
 		}
 		if err := man.Checkout(base); err != nil {
 			return err
 		}
-		err = man.Up(args...)
+		var stage string
+		if len(args) > 0 {
+			stage = args[0]
+		}
+		err = man.UpWithOpts(&variantmod.UpOpts{Stage: stage, Only: only})
 		if err != nil {
 			return err
 		}
@@ -136,13 +306,20 @@ func New(log logr.Logger) *cobra.Command {
 	{
 		var repo, branch, base, title, body string
 		var build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle bool
+		var only []string
+		var upArgs func() ([]variantmod.Option, error)
 		modup := &cobra.Command{
 			Use:  "up [STAGE]",
 			Args: cobra.MaximumNArgs(1),
 			RunE: func(cmd *cobra.Command, args []string) error {
-				return up(branch, title, body, base, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle, args)
+				opts, err := upArgs()
+				if err != nil {
+					return err
+				}
+				return up(branch, title, body, base, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle, only, args, opts...)
 			},
 		}
+		upArgs = argsFlags(modup)
 		modup.Flags().BoolVar(&build, "build", false, "Run `build` after update")
 		modup.Flags().BoolVar(&push, "push", false, "Push to Git repository after update (and `build` if --build provided)")
 		modup.Flags().StringVar(&repo, "repo", "", "Git repository to which the provisioned files are pushed")
@@ -153,6 +330,7 @@ func New(log logr.Logger) *cobra.Command {
 		modup.Flags().StringVar(&body, "body", "{{ .RawLock | sha256 }}", "Title of the pull-request to be sent")
 		modup.Flags().BoolVar(&skipDuplicatePRBody, "skip-on-duplicate-pull-request-body", false, "If true, PR creation will be skipped if the PR body is duplicated.")
 		modup.Flags().BoolVar(&skipDuplicatePRTitle, "skip-on-duplicate-pull-request-title", false, "If true, PR creation will be skipped if the PR title is duplicated.")
+		modup.Flags().StringSliceVar(&only, "only", nil, "Comma-separated names of dependencies to be updated. All the dependencies are updated when omitted")
 		cmd.AddCommand(modup)
 	}
 
@@ -174,7 +352,7 @@ func New(log logr.Logger) *cobra.Command {
 					return err
 				}
 
-				return up(branch, title, body, base, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle, nil)
+				return up(branch, title, body, base, build, push, pr, skipDuplicatePRBody, skipDuplicatePRTitle, nil, nil)
 			},
 		}
 		modcreate.Flags().BoolVar(&build, "build", true, "Run `build` after update")
@@ -203,8 +381,13 @@ func New(log logr.Logger) *cobra.Command {
 	}
 
 	cmd.AddCommand(modbuild)
+	cmd.AddCommand(moddiff)
 	cmd.AddCommand(modexec)
 	cmd.AddCommand(modlistdepver)
+	cmd.AddCommand(modoutdated)
+	cmd.AddCommand(modpin)
+	cmd.AddCommand(modunpin)
+	cmd.AddCommand(modvalidate)
 	cmd.AddCommand(modprovision)
 
 	cmd.SilenceErrors = true
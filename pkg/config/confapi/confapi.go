@@ -1,7 +1,10 @@
 package confapi
 
 import (
+	"time"
+
 	"github.com/k-kinzal/aliases/pkg/aliases/yaml"
+	"github.com/variantdev/mod/pkg/versioning"
 )
 
 type Module struct {
@@ -42,7 +45,9 @@ type ModuleParams struct {
 	Alias          string
 	LockedVersions State
 	ForceUpdate    bool
-	Module         *Module
+	// ForceUpdateOnly limits ForceUpdate to the named dependencies. All the dependencies are updated when empty.
+	ForceUpdateOnly []string
+	Module          *Module
 }
 
 type TextReplace struct {
@@ -66,6 +71,12 @@ type Patch struct {
 	From  string      `json:"from"`
 }
 
+// IgnoreRule matches releases to be ignored by the exact version like `3.13.0` or a semver range like `>= 3.13.0, < 3.13.2`
+type IgnoreRule struct {
+	Version string `yaml:"version"`
+	Reason  string `yaml:"reason"`
+}
+
 type Dependency struct {
 	ReleasesFrom VersionsFrom
 
@@ -74,6 +85,15 @@ type Dependency struct {
 	// VersionConstraint is the version range for this dependency. Works only for modules hosted on Git or GitHub
 	VersionConstraint string
 
+	// MinimumReleaseAge holds back releases published more recently than this duration ago
+	MinimumReleaseAge time.Duration
+
+	// UpdatePolicy is either "patch", "minor" or "major", which further restricts VersionConstraint relative to the locked version
+	UpdatePolicy string
+
+	// Ignore excludes known bad releases of this dependency
+	Ignore []IgnoreRule
+
 	Arguments func(map[string]interface{}) (map[string]interface{}, error)
 
 	Alias          string
@@ -100,7 +120,10 @@ func (l State) ToMap() map[string]interface{} {
 func (l State) ToDepsMap() map[string]interface{} {
 	deps := map[string]interface{}{}
 	for k, v := range l.Dependencies {
-		m := map[string]interface{}{"version": v.Version}
+		m := map[string]interface{}{"version": v.Version, "tag": v.Version}
+		if v.Tag != "" {
+			m["tag"] = v.Tag
+		}
 		if v.PreviousVersion != "" {
 			m["previousVersion"] = v.PreviousVersion
 		}
@@ -141,11 +164,30 @@ type VersionsFrom struct {
 	GitTags         GitTags
 	GitHubTags      GitHubTags
 	GitHubReleases  GitHubReleases
+	GitLabTags      GitLabTags
+	GitLabReleases  GitLabReleases
+	GiteaReleases   GiteaReleases
 	DockerImageTags DockerImageTags
+	OCIArtifactTags OCIArtifactTags
+	HelmChart       HelmChart
+	GoModule        GoModule
+	NpmPackage      NpmPackage
+	PyPIPackage     PyPIPackage
+	MavenArtifact   MavenArtifact
+
+	TerraformProvider TerraformProvider
+	TerraformModule   TerraformModule
 
 	// ValidVersionPattern is the regular expression that should match only against valid version numbers for this dependency.
 	// Used for filtering out unnecessary, unexpected or invalid version numbers from being used for dependency updates.
 	ValidVersionPattern string
+
+	// VersionPattern is the regular expression to extract the version out of each tag, like `^chart/v(.+)$`.
+	// The capture group named `version`, or the first capture group, is used as the version.
+	VersionPattern string
+
+	// Versioning is the scheme for parsing and ordering versions of this dependency, which defaults to semver
+	Versioning versioning.Spec
 }
 
 type Exec struct {
@@ -171,11 +213,81 @@ type GitHubTags struct {
 type GitHubReleases struct {
 	Host   string
 	Source func(map[string]interface{}) (string, error)
+
+	IncludePrereleases bool
+	IncludeDrafts      bool
 }
 
-type DockerImageTags struct {
-	Source func(map[string]interface{}) (string, error)
+type GitLabTags struct {
+	BaseURL string
+	Source  func(map[string]interface{}) (string, error)
+}
+
+type GitLabReleases struct {
+	BaseURL string
+	Source  func(map[string]interface{}) (string, error)
+}
+
+type GiteaReleases struct {
 	Host   string
+	Source func(map[string]interface{}) (string, error)
+	Token  string
+
+	IncludePrereleases bool
+	IncludeDrafts      bool
+}
+
+type DockerImageTags struct {
+	Source    func(map[string]interface{}) (string, error)
+	Host      string
+	Platforms []string
+}
+
+type OCIArtifactTags struct {
+	Source    func(map[string]interface{}) (string, error)
+	Host      string
+	MediaType string
+}
+
+type HelmChart struct {
+	Repository func(map[string]interface{}) (string, error)
+	Chart      string
+}
+
+type GoModule struct {
+	Path                func(map[string]interface{}) (string, error)
+	IncludeIncompatible bool
+}
+
+type NpmPackage struct {
+	Name     func(map[string]interface{}) (string, error)
+	Registry string
+	DistTag  string
+}
+
+type PyPIPackage struct {
+	Name  func(map[string]interface{}) (string, error)
+	Index string
+}
+
+type MavenArtifact struct {
+	Repository string
+	GroupID    string
+	ArtifactID func(map[string]interface{}) (string, error)
+	ServerID   string
+}
+
+type TerraformProvider struct {
+	Host      string
+	Namespace string
+	Type      func(map[string]interface{}) (string, error)
+}
+
+type TerraformModule struct {
+	Host      string
+	Namespace string
+	Name      func(map[string]interface{}) (string, error)
+	System    string
 }
 
 type Stage struct {
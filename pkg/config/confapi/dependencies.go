@@ -1,5 +1,7 @@
 package confapi
 
+import "time"
+
 type VersionedDependencyStateMeta map[string]DependencyStateMeta
 
 type DependencyStateMeta map[string]interface{}
@@ -9,6 +11,16 @@ type DependencyState struct {
 	PreviousVersion string                 `yaml:"previousVersion,omitempty"`
 	Meta            map[string]interface{} `yaml:",inline"`
 
+	// Tag is the original tag of the version, recorded only when it differs from Version, like `chart/v1.2.3` for `1.2.3`
+	Tag string `yaml:"tag,omitempty"`
+
 	Versions []string `yaml:"versions,omitempty"`
+
+	// Pinned is true when the version is explicitly pinned via `mod pin`, so that `mod up` won't update it
+	Pinned bool `yaml:"pinned,omitempty"`
+
+	// FirstSeen is the time each version newer than Version was first seen.
+	// Recorded only for providers that don't know publication times of releases, to enforce the minimum release age.
+	FirstSeen map[string]time.Time `yaml:"firstSeen,omitempty"`
 }
 
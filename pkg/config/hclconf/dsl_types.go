@@ -33,9 +33,30 @@ type Dependency struct {
 
 	ValidVersionPattern *string `hcl:"valid_version_pattern,attr"`
 
+	VersionPattern *string `hcl:"version_pattern,attr"`
+
+	Versioning *Versioning `hcl:"versioning,block"`
+
+	MinimumReleaseAge *string `hcl:"minimum_release_age,attr"`
+
+	UpdatePolicy *string `hcl:"update_policy,attr"`
+
+	Ignore []Ignore `hcl:"ignore,block"`
+
 	BodyForType hcl2.Body `hcl:",remain"`
 }
 
+type Versioning struct {
+	Scheme  string    `hcl:"scheme,attr"`
+	Pattern *string   `hcl:"pattern,attr"`
+	SortBy  *[]string `hcl:"sort_by,attr"`
+}
+
+type Ignore struct {
+	Version string  `hcl:"version,attr"`
+	Reason  *string `hcl:"reason,attr"`
+}
+
 type ExecDependency struct {
 	Command string   `hcl:"command,attr"`
 	Args    []string `hcl:"args,attr"`
@@ -59,11 +80,81 @@ type GitHubTags struct {
 type GitHubReleases struct {
 	Host   *string `hcl:"host,attr"`
 	Source string  `hcl:"source,attr"`
+
+	IncludePrereleases *bool `hcl:"include_prereleases,attr"`
+	IncludeDrafts      *bool `hcl:"include_drafts,attr"`
 }
 
-type DockerImageTags struct {
-	Host   *string `hcl:"host,attr"`
+type GitLabTags struct {
+	BaseURL *string `hcl:"base_url,attr"`
+	Source  string  `hcl:"source,attr"`
+}
+
+type GitLabReleases struct {
+	BaseURL *string `hcl:"base_url,attr"`
+	Source  string  `hcl:"source,attr"`
+}
+
+type GiteaReleases struct {
+	Host   string  `hcl:"host,attr"`
 	Source string  `hcl:"source,attr"`
+	Token  *string `hcl:"token,attr"`
+
+	IncludePrereleases *bool `hcl:"include_prereleases,attr"`
+	IncludeDrafts      *bool `hcl:"include_drafts,attr"`
+}
+
+type DockerImageTags struct {
+	Host      *string   `hcl:"host,attr"`
+	Source    string    `hcl:"source,attr"`
+	Platforms *[]string `hcl:"platforms,attr"`
+}
+
+type OCIArtifactTags struct {
+	Host      string  `hcl:"host,attr"`
+	Source    string  `hcl:"source,attr"`
+	MediaType *string `hcl:"media_type,attr"`
+}
+
+type HelmChart struct {
+	Repository string `hcl:"repository,attr"`
+	Chart      string `hcl:"chart,attr"`
+}
+
+type GoModule struct {
+	Path                string `hcl:"path,attr"`
+	IncludeIncompatible *bool  `hcl:"include_incompatible,attr"`
+}
+
+type NpmPackage struct {
+	Name     string  `hcl:"name,attr"`
+	Registry *string `hcl:"registry,attr"`
+	DistTag  *string `hcl:"dist_tag,attr"`
+}
+
+type PyPIPackage struct {
+	Name  string  `hcl:"name,attr"`
+	Index *string `hcl:"index,attr"`
+}
+
+type MavenArtifact struct {
+	Repository *string `hcl:"repository,attr"`
+	GroupID    string  `hcl:"group_id,attr"`
+	ArtifactID string  `hcl:"artifact_id,attr"`
+	ServerID   *string `hcl:"server_id,attr"`
+}
+
+type TerraformProvider struct {
+	Host      *string `hcl:"host,attr"`
+	Namespace string  `hcl:"namespace,attr"`
+	Type      string  `hcl:"type,attr"`
+}
+
+type TerraformModule struct {
+	Host      *string `hcl:"host,attr"`
+	Namespace string  `hcl:"namespace,attr"`
+	Name      string  `hcl:"name,attr"`
+	System    string  `hcl:"system,attr"`
 }
 
 type File struct {
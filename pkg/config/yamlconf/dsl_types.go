@@ -7,6 +7,8 @@ import (
 	"github.com/variantdev/mod/pkg/execversionmanager"
 	"github.com/variantdev/mod/pkg/maputil"
 	"github.com/variantdev/mod/pkg/tmpl"
+	"github.com/variantdev/mod/pkg/versioning"
+	"gopkg.in/yaml.v3"
 )
 
 type ModuleSpec struct {
@@ -29,9 +31,45 @@ type VersionsFrom struct {
 	GitTags         GitTags         `yaml:"gitTags"`
 	GitHubTags      GitHubTags      `yaml:"githubTags"`
 	GitHubReleases  GitHubReleases  `yaml:"githubReleases"`
+	GitLabTags      GitLabTags      `yaml:"gitlabTags"`
+	GitLabReleases  GitLabReleases  `yaml:"gitlabReleases"`
+	GiteaReleases   GiteaReleases   `yaml:"giteaReleases"`
 	DockerImageTags DockerImageTags `yaml:"dockerImageTags"`
+	OCIArtifactTags OCIArtifactTags `yaml:"ociArtifactTags"`
+	HelmChart       HelmChart       `yaml:"helmChart"`
+	GoModule        GoModule        `yaml:"goModule"`
+	NpmPackage      NpmPackage      `yaml:"npmPackage"`
+	PyPIPackage     PyPIPackage     `yaml:"pypiPackage"`
+	MavenArtifact   MavenArtifact   `yaml:"mavenArtifact"`
+
+	TerraformProvider TerraformProvider `yaml:"terraformProvider"`
+	TerraformModule   TerraformModule   `yaml:"terraformModule"`
 
 	ValidVersionPattern string `yaml:"validVersionPattern"`
+	// VersionPattern extracts the version out of each tag with the capture group, like `^chart/v(.+)$`
+	VersionPattern string `yaml:"versionPattern"`
+
+	// Versioning is either the name of the scheme like `calver`, or the spec of the scheme including the pattern for `regex`
+	Versioning Versioning `yaml:"versioning"`
+}
+
+type Versioning versioning.Spec
+
+// UnmarshalYAML accepts `versioning: calver` as the shorthand of `versioning: {scheme: calver}`
+func (v *Versioning) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		v.Scheme = node.Value
+		return nil
+	}
+
+	var spec versioning.Spec
+	if err := node.Decode(&spec); err != nil {
+		return err
+	}
+
+	*v = Versioning(spec)
+
+	return nil
 }
 
 func (f VersionsFrom) IsDefined() bool {
@@ -39,7 +77,18 @@ func (f VersionsFrom) IsDefined() bool {
 		f.JSONPath.Source != "" ||
 		f.GitTags.Source != "" ||
 		f.GitHubReleases.Source != "" ||
-		f.DockerImageTags.Source != ""
+		f.GitLabTags.Source != "" ||
+		f.GitLabReleases.Source != "" ||
+		f.GiteaReleases.Source != "" ||
+		f.DockerImageTags.Source != "" ||
+		f.OCIArtifactTags.Source != "" ||
+		f.HelmChart.Repository != "" ||
+		f.GoModule.Path != "" ||
+		f.NpmPackage.Name != "" ||
+		f.PyPIPackage.Name != "" ||
+		f.MavenArtifact.ArtifactID != "" ||
+		f.TerraformProvider.Type != "" ||
+		f.TerraformModule.Name != ""
 }
 
 func ToVersionsFrom(v VersionsFrom) confapi.VersionsFrom {
@@ -48,15 +97,52 @@ func ToVersionsFrom(v VersionsFrom) confapi.VersionsFrom {
 	r.Exec.Command = v.Exec.Command
 	r.DockerImageTags.Source = NewRender("dockerimageTags.source", v.DockerImageTags.Source)
 	r.DockerImageTags.Host = v.DockerImageTags.Host
+	r.DockerImageTags.Platforms = v.DockerImageTags.Platforms
+	r.OCIArtifactTags.Source = NewRender("ociArtifactTags.source", v.OCIArtifactTags.Source)
+	r.OCIArtifactTags.Host = v.OCIArtifactTags.Host
+	r.OCIArtifactTags.MediaType = v.OCIArtifactTags.MediaType
+	r.HelmChart.Repository = NewRender("helmChart.repository", v.HelmChart.Repository)
+	r.HelmChart.Chart = v.HelmChart.Chart
+	r.GoModule.Path = NewRender("goModule.path", v.GoModule.Path)
+	r.GoModule.IncludeIncompatible = v.GoModule.IncludeIncompatible
+	r.NpmPackage.Name = NewRender("npmPackage.name", v.NpmPackage.Name)
+	r.NpmPackage.Registry = v.NpmPackage.Registry
+	r.NpmPackage.DistTag = v.NpmPackage.DistTag
+	r.PyPIPackage.Name = NewRender("pypiPackage.name", v.PyPIPackage.Name)
+	r.PyPIPackage.Index = v.PyPIPackage.Index
+	r.MavenArtifact.Repository = v.MavenArtifact.Repository
+	r.MavenArtifact.GroupID = v.MavenArtifact.GroupID
+	r.MavenArtifact.ArtifactID = NewRender("mavenArtifact.artifactId", v.MavenArtifact.ArtifactID)
+	r.MavenArtifact.ServerID = v.MavenArtifact.ServerID
+	r.TerraformProvider.Host = v.TerraformProvider.Host
+	r.TerraformProvider.Namespace = v.TerraformProvider.Namespace
+	r.TerraformProvider.Type = NewRender("terraformProvider.type", v.TerraformProvider.Type)
+	r.TerraformModule.Host = v.TerraformModule.Host
+	r.TerraformModule.Namespace = v.TerraformModule.Namespace
+	r.TerraformModule.Name = NewRender("terraformModule.name", v.TerraformModule.Name)
+	r.TerraformModule.System = v.TerraformModule.System
 	r.GitHubReleases.Source = NewRender("githubReleases.source", v.GitHubReleases.Source)
 	r.GitHubReleases.Host = v.GitHubReleases.Host
+	r.GitHubReleases.IncludePrereleases = v.GitHubReleases.IncludePrereleases
+	r.GitHubReleases.IncludeDrafts = v.GitHubReleases.IncludeDrafts
 	r.GitHubTags.Source = NewRender("githubTags.source", v.GitHubTags.Source)
 	r.GitHubTags.Host = v.GitHubTags.Host
+	r.GitLabReleases.Source = NewRender("gitlabReleases.source", v.GitLabReleases.Source)
+	r.GitLabReleases.BaseURL = v.GitLabReleases.BaseURL
+	r.GitLabTags.Source = NewRender("gitlabTags.source", v.GitLabTags.Source)
+	r.GitLabTags.BaseURL = v.GitLabTags.BaseURL
+	r.GiteaReleases.Source = NewRender("giteaReleases.source", v.GiteaReleases.Source)
+	r.GiteaReleases.Host = v.GiteaReleases.Host
+	r.GiteaReleases.Token = v.GiteaReleases.Token
+	r.GiteaReleases.IncludePrereleases = v.GiteaReleases.IncludePrereleases
+	r.GiteaReleases.IncludeDrafts = v.GiteaReleases.IncludeDrafts
 	r.GitTags.Source = NewRender("gitTags.source", v.GitTags.Source)
 	r.JSONPath.Source = NewRender("jsonPath.source", v.JSONPath.Source)
 	r.JSONPath.Description = v.JSONPath.Description
 	r.JSONPath.Versions = v.JSONPath.Versions
 	r.ValidVersionPattern = v.ValidVersionPattern
+	r.VersionPattern = v.VersionPattern
+	r.Versioning = versioning.Spec(v.Versioning)
 	return r
 }
 
@@ -83,11 +169,86 @@ type GitHubTags struct {
 type GitHubReleases struct {
 	Host   string `yaml:"host"`
 	Source string `yaml:"source"`
+
+	// IncludePrereleases includes releases marked as pre-release on GitHub
+	IncludePrereleases bool `yaml:"includePrereleases"`
+	// IncludeDrafts includes draft releases, that are visible only to users with push access to the repository
+	IncludeDrafts bool `yaml:"includeDrafts"`
 }
 
-type DockerImageTags struct {
-	Source string `yaml:"source"`
+type GitLabTags struct {
+	BaseURL string `yaml:"baseURL"`
+	Source  string `yaml:"source"`
+}
+
+type GitLabReleases struct {
+	BaseURL string `yaml:"baseURL"`
+	Source  string `yaml:"source"`
+}
+
+type GiteaReleases struct {
 	Host   string `yaml:"host"`
+	Source string `yaml:"source"`
+	// Token is the access token, which defaults to GITEA_TOKEN
+	Token string `yaml:"token"`
+
+	// IncludePrereleases includes releases marked as pre-release on Gitea
+	IncludePrereleases bool `yaml:"includePrereleases"`
+	// IncludeDrafts includes draft releases, that are visible only to users with write access to the repository
+	IncludeDrafts bool `yaml:"includeDrafts"`
+}
+
+type DockerImageTags struct {
+	Source    string   `yaml:"source"`
+	Host      string   `yaml:"host"`
+	Platforms []string `yaml:"platforms"`
+}
+
+type OCIArtifactTags struct {
+	Source    string `yaml:"source"`
+	Host      string `yaml:"host"`
+	MediaType string `yaml:"mediaType"`
+}
+
+type HelmChart struct {
+	Repository string `yaml:"repository"`
+	Chart      string `yaml:"chart"`
+}
+
+type GoModule struct {
+	Path                string `yaml:"path"`
+	IncludeIncompatible bool   `yaml:"includeIncompatible"`
+}
+
+type NpmPackage struct {
+	Name     string `yaml:"name"`
+	Registry string `yaml:"registry"`
+	DistTag  string `yaml:"distTag"`
+}
+
+type PyPIPackage struct {
+	Name  string `yaml:"name"`
+	Index string `yaml:"index"`
+}
+
+type MavenArtifact struct {
+	Repository string `yaml:"repository"`
+	GroupID    string `yaml:"groupId"`
+	ArtifactID string `yaml:"artifactId"`
+	ServerID   string `yaml:"serverId"`
+}
+
+type TerraformProvider struct {
+	Host      string `yaml:"host"`
+	Namespace string `yaml:"namespace"`
+	Type      string `yaml:"type"`
+}
+
+type TerraformModule struct {
+	Host      string `yaml:"host"`
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	System    string `yaml:"system"`
 }
 
 type ParametersSpec struct {
@@ -237,6 +398,12 @@ type DependencySpec struct {
 	// VersionConstraint is the version range for this dependency. Works only for modules hosted on Git or GitHub
 	VersionConstraint string                 `yaml:"version"`
 	Arguments         map[string]interface{} `yaml:"arguments"`
+	// MinimumReleaseAge is the duration like `72h` that a release needs to be published for before being adopted
+	MinimumReleaseAge string `yaml:"minimumReleaseAge"`
+	// UpdatePolicy is either `patch`, `minor` or `major`, which limits updates relative to the locked version
+	UpdatePolicy string `yaml:"updatePolicy"`
+	// Ignore is the list of releases to be ignored, each with an optional reason
+	Ignore []confapi.IgnoreRule `yaml:"ignore"`
 
 	Alias          string
 	LockedVersions confapi.State
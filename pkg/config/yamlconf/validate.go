@@ -0,0 +1,251 @@
+package yamlconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/variantdev/mod/pkg/dockerregistry"
+	"github.com/variantdev/mod/pkg/maputil"
+	"github.com/variantdev/mod/pkg/releasetracker"
+	"github.com/variantdev/mod/pkg/tmpl"
+	"github.com/variantdev/mod/pkg/versioning"
+)
+
+// Validate checks the syntax of every template contained in the module spec and the definitions of release providers.
+// It never renders templates nor fetches anything, so that it can be used to validate the module offline.
+func (s *ModuleSpec) Validate() []error {
+	var errs []error
+
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field, err))
+		}
+	}
+
+	for path, f := range s.Provisioners.Files {
+		field := fmt.Sprintf("provisioners.files[%q]", path)
+		add(field, tmpl.Parse("file.path", path))
+		add(field+".path", tmpl.Parse("file.path", f.Path))
+		add(field+".source", tmpl.Parse("file.source", f.Source))
+		add(field+".arguments", parseArgs(f.Arguments))
+	}
+
+	for path, d := range s.Provisioners.Directories {
+		field := fmt.Sprintf("provisioners.directories[%q]", path)
+		add(field+".source", tmpl.Parse("directory.source", d.Source))
+		add(field+".arguments", parseArgs(d.Arguments))
+		for pat, t := range d.Templates {
+			tfield := fmt.Sprintf("%s.templates[%q]", field, pat)
+			_, err := regexp.Compile(pat)
+			add(tfield, err)
+			add(tfield+".arguments", parseArgs(t.Arguments))
+		}
+	}
+
+	for path, t := range s.Provisioners.TextReplace {
+		field := fmt.Sprintf("provisioners.textReplace[%q]", path)
+		add(field, tmpl.Parse("textReplace.path", path))
+		add(field+".from", tmpl.Parse("textReplace.from", t.From))
+		add(field+".to", tmpl.Parse("textReplace.to", t.To))
+	}
+
+	for path, r := range s.Provisioners.RegexpReplace {
+		field := fmt.Sprintf("provisioners.regexpReplace[%q]", path)
+		add(field, tmpl.Parse("regexpReplace.path", path))
+		_, err := regexp.Compile(r.From)
+		add(field+".from", err)
+		add(field+".to", tmpl.Parse("regexpReplace.to", r.To))
+	}
+
+	for path, patches := range s.Provisioners.YamlPatch {
+		field := fmt.Sprintf("provisioners.yamlPatch[%q]", path)
+		add(field, tmpl.Parse("yamlPatch.path", path))
+		out, err := json.Marshal(patches)
+		if err != nil {
+			add(field, err)
+			continue
+		}
+		add(field, tmpl.Parse("yamlPatch.patches", string(out)))
+	}
+
+	for name, e := range s.Provisioners.Executables.Executables {
+		for i, p := range e.Platforms {
+			field := fmt.Sprintf("provisioners.executables[%q].platforms[%d]", name, i)
+			add(field+".source", tmpl.Parse("platform.source", p.Source))
+			add(field+".docker.tag", tmpl.Parse("platform.docker.tag", p.Docker.Tag))
+		}
+	}
+
+	for name, d := range s.Dependencies {
+		field := fmt.Sprintf("dependencies[%q]", name)
+		add(field+".arguments", parseArgs(d.Arguments))
+		if d.MinimumReleaseAge != "" {
+			_, err := time.ParseDuration(d.MinimumReleaseAge)
+			add(field+".minimumReleaseAge", err)
+		}
+		switch d.UpdatePolicy {
+		case "", "patch", "minor", "major":
+		default:
+			add(field+".updatePolicy", fmt.Errorf("unsupported update policy %q: must be one of patch, minor, or major", d.UpdatePolicy))
+		}
+		for i, ig := range d.Ignore {
+			add(fmt.Sprintf("%s.ignore[%d].version", field, i), releasetracker.ValidateIgnoreRule(ig.Version))
+		}
+		if d.ReleasesFrom.IsDefined() {
+			errs = append(errs, d.ReleasesFrom.validate(field+".releasesFrom")...)
+		}
+	}
+
+	for name, r := range s.Releases {
+		field := fmt.Sprintf("releases[%q].versionsFrom", name)
+		errs = append(errs, r.VersionsFrom.validate(field)...)
+	}
+
+	// Sort for stable output, as all the fields are iterated in map order
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errs
+}
+
+func (f VersionsFrom) validate(field string) []error {
+	var errs []error
+
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field, err))
+		}
+	}
+
+	var providers []string
+	if f.Exec.Command != "" {
+		providers = append(providers, "exec")
+	}
+	if f.JSONPath.Source != "" {
+		providers = append(providers, "jsonPath")
+		add(field+".jsonPath.source", tmpl.Parse("jsonPath.source", f.JSONPath.Source))
+		if f.JSONPath.Versions == "" {
+			add(field+".jsonPath", fmt.Errorf("versions is required"))
+		}
+	}
+	if f.GitTags.Source != "" {
+		providers = append(providers, "gitTags")
+		add(field+".gitTags.source", tmpl.Parse("gitTags.source", f.GitTags.Source))
+	}
+	if f.GitHubTags.Source != "" {
+		providers = append(providers, "githubTags")
+		add(field+".githubTags.source", tmpl.Parse("githubTags.source", f.GitHubTags.Source))
+	}
+	if f.GitHubReleases.Source != "" {
+		providers = append(providers, "githubReleases")
+		add(field+".githubReleases.source", tmpl.Parse("githubReleases.source", f.GitHubReleases.Source))
+	}
+	if f.GitLabTags.Source != "" {
+		providers = append(providers, "gitlabTags")
+		add(field+".gitlabTags.source", tmpl.Parse("gitlabTags.source", f.GitLabTags.Source))
+	}
+	if f.GitLabReleases.Source != "" {
+		providers = append(providers, "gitlabReleases")
+		add(field+".gitlabReleases.source", tmpl.Parse("gitlabReleases.source", f.GitLabReleases.Source))
+	}
+	if f.GiteaReleases.Source != "" {
+		providers = append(providers, "giteaReleases")
+		add(field+".giteaReleases.source", tmpl.Parse("giteaReleases.source", f.GiteaReleases.Source))
+		if f.GiteaReleases.Host == "" {
+			add(field+".giteaReleases", fmt.Errorf("host is required"))
+		}
+	}
+	if f.DockerImageTags.Source != "" {
+		providers = append(providers, "dockerImageTags")
+		add(field+".dockerImageTags.source", tmpl.Parse("dockerImageTags.source", f.DockerImageTags.Source))
+		for i, p := range f.DockerImageTags.Platforms {
+			_, err := dockerregistry.ParsePlatform(p)
+			add(fmt.Sprintf("%s.dockerImageTags.platforms[%d]", field, i), err)
+		}
+	}
+	if f.OCIArtifactTags.Source != "" {
+		providers = append(providers, "ociArtifactTags")
+		add(field+".ociArtifactTags.source", tmpl.Parse("ociArtifactTags.source", f.OCIArtifactTags.Source))
+		if f.OCIArtifactTags.Host == "" {
+			add(field+".ociArtifactTags", fmt.Errorf("host is required"))
+		}
+	}
+	if f.HelmChart.Repository != "" {
+		providers = append(providers, "helmChart")
+		add(field+".helmChart.repository", tmpl.Parse("helmChart.repository", f.HelmChart.Repository))
+		if f.HelmChart.Chart == "" {
+			add(field+".helmChart", fmt.Errorf("chart is required"))
+		}
+	}
+	if f.GoModule.Path != "" {
+		providers = append(providers, "goModule")
+		add(field+".goModule.path", tmpl.Parse("goModule.path", f.GoModule.Path))
+	}
+	if f.NpmPackage.Name != "" {
+		providers = append(providers, "npmPackage")
+		add(field+".npmPackage.name", tmpl.Parse("npmPackage.name", f.NpmPackage.Name))
+	}
+	if f.PyPIPackage.Name != "" {
+		providers = append(providers, "pypiPackage")
+		add(field+".pypiPackage.name", tmpl.Parse("pypiPackage.name", f.PyPIPackage.Name))
+	}
+	if f.MavenArtifact.ArtifactID != "" {
+		providers = append(providers, "mavenArtifact")
+		add(field+".mavenArtifact.artifactId", tmpl.Parse("mavenArtifact.artifactId", f.MavenArtifact.ArtifactID))
+		if f.MavenArtifact.GroupID == "" {
+			add(field+".mavenArtifact", fmt.Errorf("groupId is required"))
+		}
+	}
+	if f.TerraformProvider.Type != "" {
+		providers = append(providers, "terraformProvider")
+		add(field+".terraformProvider.type", tmpl.Parse("terraformProvider.type", f.TerraformProvider.Type))
+		if f.TerraformProvider.Namespace == "" {
+			add(field+".terraformProvider", fmt.Errorf("namespace is required"))
+		}
+	}
+	if f.TerraformModule.Name != "" {
+		providers = append(providers, "terraformModule")
+		add(field+".terraformModule.name", tmpl.Parse("terraformModule.name", f.TerraformModule.Name))
+		if f.TerraformModule.Namespace == "" {
+			add(field+".terraformModule", fmt.Errorf("namespace is required"))
+		}
+		if f.TerraformModule.System == "" {
+			add(field+".terraformModule", fmt.Errorf("system is required"))
+		}
+	}
+
+	switch len(providers) {
+	case 0:
+		add(field, fmt.Errorf("no release provider defined"))
+	case 1:
+	default:
+		add(field, fmt.Errorf("only one release provider can be defined, but got %v", providers))
+	}
+
+	if f.ValidVersionPattern != "" {
+		_, err := regexp.Compile(f.ValidVersionPattern)
+		add(field+".validVersionPattern", err)
+	}
+
+	if f.VersionPattern != "" {
+		_, err := releasetracker.CompileVersionPattern(f.VersionPattern)
+		add(field+".versionPattern", err)
+	}
+
+	_, err := versioning.New(versioning.Spec(f.Versioning))
+	add(field+".versioning", err)
+
+	return errs
+}
+
+func parseArgs(args map[string]interface{}) error {
+	a, err := maputil.CastKeysToStrings(args)
+	if err != nil {
+		return err
+	}
+	return tmpl.ParseArgs(a)
+}
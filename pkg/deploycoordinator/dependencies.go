@@ -4,24 +4,37 @@ import (
 	"fmt"
 
 	"github.com/variantdev/mod/pkg/config/confapi"
-	"github.com/variantdev/mod/pkg/semver"
+	"github.com/variantdev/mod/pkg/versioning"
 )
 
 type DependencyManager struct {
 	State map[string]confapi.DependencyState
 
 	StateMeta map[string]confapi.VersionedDependencyStateMeta
+
+	// Versioning is the versioning scheme of each dependency. Dependencies missing in it are versioned with semver
+	Versioning map[string]versioning.Scheme
 }
 
 func (s *DependencyManager) AddDependencyUpdate(name, version string) error {
-	return addDependencyUpdate(s.State, name, version)
+	return addDependencyUpdate(s.State, s.Versioning, name, version)
 }
 
 func (s *DependencyManager) UpdateDependencies(deps []string, f func(depName string) ([]DependencyEntry, error)) error {
-	return updateDependencies(deps, s.State, s.StateMeta, f)
+	return updateDependencies(deps, s.State, s.StateMeta, s.Versioning, f)
+}
+
+// isNewer returns true when the version is newer than the latest version in the dependency's versioning scheme
+func isNewer(schemes map[string]versioning.Scheme, name, version, latest string) (bool, error) {
+	c, err := versioning.Or(schemes[name]).Compare(version, latest)
+	if err != nil {
+		return false, fmt.Errorf("comparing versions %q and %q of %q: %w", version, latest, name, err)
+	}
+
+	return c > 0, nil
 }
 
-func addDependencyUpdate(existingDeps map[string]confapi.DependencyState, name, version string) error {
+func addDependencyUpdate(existingDeps map[string]confapi.DependencyState, schemes map[string]versioning.Scheme, name, version string) error {
 	dep, ok := existingDeps[name]
 	if !ok {
 		return fmt.Errorf("getting dependency: %q not found", name)
@@ -29,24 +42,19 @@ func addDependencyUpdate(existingDeps map[string]confapi.DependencyState, name,
 
 	latest := dep.Versions[len(dep.Versions)-1]
 
-	latestV, err := semver.Parse(latest)
+	newer, err := isNewer(schemes, name, version, latest)
 	if err != nil {
-		return fmt.Errorf("parsing %q as semver: %w", latest, err)
+		return err
 	}
 
-	newV, err := semver.Parse(version)
-	if err != nil {
-		return fmt.Errorf("parsing %q as semver: %w", version, err)
-	}
-
-	if newV.GreaterThan(latestV) {
+	if newer {
 		dep.Versions = append(dep.Versions, version)
 	}
 
 	return nil
 }
 
-func updateDependencies(deps []string, existingDeps map[string]confapi.DependencyState, meta map[string]confapi.VersionedDependencyStateMeta, f func(depName string) ([]DependencyEntry, error)) error {
+func updateDependencies(deps []string, existingDeps map[string]confapi.DependencyState, meta map[string]confapi.VersionedDependencyStateMeta, schemes map[string]versioning.Scheme, f func(depName string) ([]DependencyEntry, error)) error {
 	for _, k := range deps {
 		dep := existingDeps[k]
 
@@ -55,27 +63,25 @@ func updateDependencies(deps []string, existingDeps map[string]confapi.Dependenc
 			return fmt.Errorf("udpating dependencies: %w", err)
 		}
 
-		var latestV *semver.Version
+		var latest string
 
 		if len(dep.Versions) > 0 {
-			latest := dep.Versions[len(dep.Versions)-1]
-
-			latestV, err = semver.Parse(latest)
-			if err != nil {
-				return fmt.Errorf("parsing %q as semver: %w", latest, err)
-			}
+			latest = dep.Versions[len(dep.Versions)-1]
 		}
 
 		var updated bool
 
 		for _, d := range fetchedDeps {
 			if version := d.Version; version != "" {
-				newV, err := semver.Parse(version)
-				if err != nil {
-					return fmt.Errorf("parsing %q as semver: %w", version, err)
+				newer := latest == ""
+				if !newer {
+					newer, err = isNewer(schemes, k, version, latest)
+					if err != nil {
+						return err
+					}
 				}
 
-				if latestV == nil || newV.GreaterThan(latestV) {
+				if newer {
 					updated = true
 
 					dep.Versions = append(dep.Versions, version)
@@ -55,7 +55,7 @@ func (s *MultiState) DeploymentUpdateDependencies(deploymentName string, depPatt
 		return fmt.Errorf("getting latest dependency set revision: %w", err)
 	}
 
-	updated, err := updateRevisions(s.Dependencies, current, p.Revisions, depPattern, requiredDepToConstraint)
+	updated, err := updateRevisions(s.Dependencies, current, p.Revisions, depPattern, requiredDepToConstraint, nil)
 	if err != nil {
 		return err
 	}
@@ -66,11 +66,11 @@ func (s *MultiState) DeploymentUpdateDependencies(deploymentName string, depPatt
 }
 
 func (s *MultiState) AddDependencyUpdate(name, version string) error {
-	return addDependencyUpdate(s.Dependencies, name, version)
+	return addDependencyUpdate(s.Dependencies, nil, name, version)
 }
 
 func (s *MultiState) UpdateDependencies(deps []string, f func(depName string) ([]DependencyEntry, error)) error {
-	return updateDependencies(deps, s.Dependencies, s.Meta.Dependencies, f)
+	return updateDependencies(deps, s.Dependencies, s.Meta.Dependencies, nil, f)
 }
 
 func ParseMultiState(doc string) (*MultiState, error) {
@@ -3,12 +3,16 @@ package deploycoordinator
 import (
 	"errors"
 	"fmt"
-	"github.com/Masterminds/semver"
 	"github.com/variantdev/mod/pkg/config/confapi"
+	"github.com/variantdev/mod/pkg/semver"
+	"github.com/variantdev/mod/pkg/versioning"
 )
 
 type RevisionManager struct {
 	Revisions []confapi.Revision `yaml:"revisions"`
+
+	// Versioning is the versioning scheme of each dependency. Dependencies missing in it are versioned with semver
+	Versioning map[string]versioning.Scheme `yaml:"-"`
 }
 
 func (s *RevisionManager) GetRevisions() ([]confapi.Revision, error) {
@@ -34,7 +38,7 @@ func (s *RevisionManager) UpdateRevisions(deps map[string]confapi.DependencyStat
 		return fmt.Errorf("updating revisions: %w", err)
 	}
 
-	updated, err := updateRevisions(deps, current, s.Revisions, depPattern, requiredDepToConstraint)
+	updated, err := updateRevisions(deps, current, s.Revisions, depPattern, requiredDepToConstraint, s.Versioning)
 	if err != nil {
 		return err
 	}
@@ -44,7 +48,7 @@ func (s *RevisionManager) UpdateRevisions(deps map[string]confapi.DependencyStat
 	return nil
 }
 
-func updateRevisions(fetchedDeps map[string]confapi.DependencyState, current *confapi.Revision, revs []confapi.Revision, depPattern string, requiredDepToConstraint map[string]string) ([]confapi.Revision, error) {
+func updateRevisions(fetchedDeps map[string]confapi.DependencyState, current *confapi.Revision, revs []confapi.Revision, depPattern string, requiredDepToConstraint map[string]string, schemes map[string]versioning.Scheme) ([]confapi.Revision, error) {
 	vers := map[string]string{}
 
 	var anyNew bool
@@ -61,14 +65,11 @@ func updateRevisions(fetchedDeps map[string]confapi.DependencyState, current *co
 			}
 		}
 
-		var curV *semver.Version
+		scheme := versioning.Or(schemes[dep])
 
 		curVer, ok := current.Versions[dep]
-		if !ok {
-			curV = semver.MustParse("v0.0.0")
-		} else {
-			curV, err = semver.NewVersion(curVer)
-			if err != nil {
+		if ok {
+			if _, err := scheme.Parse(curVer); err != nil {
 				return nil, fmt.Errorf("getting curV: %w", err)
 			}
 		}
@@ -90,15 +91,22 @@ func updateRevisions(fetchedDeps map[string]confapi.DependencyState, current *co
 					return nil, fmt.Errorf("invalid state: empty version number found for %s's version at index %d", dep, i)
 				}
 
-				newerSemver, err := semver.NewVersion(newerVer)
+				newerSemver, err := scheme.Parse(newerVer)
 				if err != nil {
 					return nil, fmt.Errorf("getting newerSemver: %w", err)
 				}
 
-				if !newerSemver.GreaterThan(curV) {
-					verToUse = curVer
+				if ok {
+					c, err := scheme.Compare(newerVer, curVer)
+					if err != nil {
+						return nil, fmt.Errorf("comparing %q to %q: %w", newerVer, curVer, err)
+					}
 
-					break
+					if c <= 0 {
+						verToUse = curVer
+
+						break
+					}
 				}
 
 				if constraints == nil || constraints.Check(newerSemver) {
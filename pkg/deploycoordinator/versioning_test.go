@@ -0,0 +1,46 @@
+package deploycoordinator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/variantdev/mod/pkg/config/confapi"
+	"github.com/variantdev/mod/pkg/versioning"
+)
+
+func TestVersioning(t *testing.T) {
+	schemes := map[string]versioning.Scheme{
+		"python": versioning.Loose,
+	}
+
+	deps := map[string]confapi.DependencyState{
+		"python": {Versions: []string{"3.12-alpine3.9"}},
+	}
+
+	err := updateDependencies([]string{"python"}, deps, map[string]confapi.VersionedDependencyStateMeta{}, schemes, func(depName string) ([]DependencyEntry, error) {
+		return []DependencyEntry{{Version: "3.12-alpine3.8"}, {Version: "3.12-alpine3.19"}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{"3.12-alpine3.9", "3.12-alpine3.19"}, deps["python"].Versions); d != "" {
+		t.Errorf("unexpected versions: %s", d)
+	}
+
+	current := &confapi.Revision{ID: 1, Versions: map[string]string{"python": "3.12-alpine3.9"}}
+
+	revs, err := updateRevisions(deps, current, []confapi.Revision{*current}, "*", map[string]string{"python": "< 4"}, schemes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []confapi.Revision{
+		{ID: 1, Versions: map[string]string{"python": "3.12-alpine3.9"}},
+		{ID: 2, Versions: map[string]string{"python": "3.12-alpine3.19"}},
+	}
+
+	if d := cmp.Diff(expected, revs); d != "" {
+		t.Errorf("unexpected revisions: %s", d)
+	}
+}
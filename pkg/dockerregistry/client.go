@@ -35,6 +35,12 @@ type Client struct {
 	username string
 	password string
 	client   *http.Client
+
+	// manifests caches manifests by repository and reference, so that a manifest is fetched only once per client
+	manifests map[string]*Manifest
+
+	// configs caches config blobs by repository and digest
+	configs map[string][]byte
 }
 
 // Option is a functional option for configuring the Client.
@@ -60,6 +66,9 @@ func New(baseURL, username, password string, opts ...Option) (*Client, error) {
 		username: username,
 		password: password,
 		client:   nil, // will be set below or by options
+
+		manifests: map[string]*Manifest{},
+		configs:   map[string][]byte{},
 	}
 	for _, opt := range opts {
 		opt(c)
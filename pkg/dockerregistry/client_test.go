@@ -182,6 +182,45 @@ func TestTags_TokenAuth(t *testing.T) {
 	}
 }
 
+func TestTags_BasicAuth(t *testing.T) {
+	var authReceived []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		authReceived = append(authReceived, auth)
+
+		if auth == "" {
+			// Registries like AWS ECR demand basic authentication
+			w.Header().Set("WWW-Authenticate", `Basic realm="https://123456789012.dkr.ecr.us-east-1.amazonaws.com/",service="ecr.amazonaws.com"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tagsResponse{Tags: []string{"v1.0.0"}})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "AWS", "testpass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := client.Tags("myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 1 || tags[0] != "v1.0.0" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+
+	expected := []string{"", "Basic QVdTOnRlc3RwYXNz"}
+	if len(authReceived) != len(expected) || authReceived[1] != expected[1] {
+		t.Errorf("expected Authorization headers %q, got %q", expected, authReceived)
+	}
+}
+
 func TestTags_NoAuthWhenCredentialsEmpty(t *testing.T) {
 	var receivedAuth string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
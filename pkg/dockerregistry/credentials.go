@@ -0,0 +1,187 @@
+package dockerregistry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// dockerHubServerURL is the key for Docker Hub in the Docker config file and the server URL given to credential helpers
+const dockerHubServerURL = "https://index.docker.io/v1/"
+
+// DockerConfig is the part of the Docker config file, usually ~/.docker/config.json, that tells how to obtain
+// the credentials for each registry.
+type DockerConfig struct {
+	Auths       map[string]AuthConfig `json:"auths"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+	CredsStore  string                `json:"credsStore"`
+
+	// RunHelper runs the credential helper. Defaults to RunCredentialHelper
+	RunHelper CredentialHelperRunner `json:"-"`
+}
+
+// AuthConfig is the credentials for a registry stored in the Docker config file.
+type AuthConfig struct {
+	// Auth is the base64-encoded `username:password`
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CredentialHelperRunner runs the `get` command of the credential helper named `docker-credential-<helper>`
+// with the server URL as the input, and returns the output.
+type CredentialHelperRunner func(helper, serverURL string) ([]byte, error)
+
+// DockerConfigPath returns the path to the Docker config file, which is `config.json` in $DOCKER_CONFIG or ~/.docker.
+func DockerConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+// ParseDockerConfig parses the content of the Docker config file.
+func ParseDockerConfig(data []byte) (*DockerConfig, error) {
+	var c DockerConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing docker config: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Credentials returns the username and the password for the registry host, like `ghcr.io`.
+// The credential helper for the host in `credHelpers` takes precedence, and `credsStore` is used for the other hosts.
+// The credentials in `auths` are used only when no `credsStore` is set, in the same way as the Docker CLI does.
+// Empty credentials are returned when none is configured for the host.
+func (c *DockerConfig) Credentials(host string) (string, string, error) {
+	host = normalizeRegistryHost(host)
+
+	var helperKeys []string
+	for key := range c.CredHelpers {
+		helperKeys = append(helperKeys, key)
+	}
+	sort.Strings(helperKeys)
+
+	for _, key := range helperKeys {
+		if normalizeRegistryHost(key) == host {
+			return c.fromHelper(c.CredHelpers[key], key)
+		}
+	}
+
+	if c.CredsStore != "" {
+		serverURL := host
+		if host == normalizeRegistryHost(dockerHubServerURL) {
+			serverURL = dockerHubServerURL
+		}
+		return c.fromHelper(c.CredsStore, serverURL)
+	}
+
+	var authKeys []string
+	for key := range c.Auths {
+		authKeys = append(authKeys, key)
+	}
+	sort.Strings(authKeys)
+
+	for _, key := range authKeys {
+		if normalizeRegistryHost(key) != host {
+			continue
+		}
+
+		a := c.Auths[key]
+
+		if a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return "", "", fmt.Errorf("decoding auth for %s: %w", key, err)
+			}
+
+			kv := strings.SplitN(string(decoded), ":", 2)
+			if len(kv) != 2 {
+				return "", "", fmt.Errorf("decoding auth for %s: expected username:password", key)
+			}
+
+			return kv[0], kv[1], nil
+		}
+
+		if a.Username != "" || a.Password != "" {
+			return a.Username, a.Password, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+type helperCredentials struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+func (c *DockerConfig) fromHelper(helper, serverURL string) (string, string, error) {
+	run := c.RunHelper
+	if run == nil {
+		run = RunCredentialHelper
+	}
+
+	out, err := run(helper, serverURL)
+	if err != nil {
+		// Helpers fail with this message when they have no credentials for the server
+		if strings.Contains(string(out), "credentials not found") || strings.Contains(err.Error(), "credentials not found") {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("getting credentials for %s from docker-credential-%s: %w", serverURL, helper, err)
+	}
+
+	var creds helperCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", "", fmt.Errorf("decoding credentials for %s from docker-credential-%s: %w", serverURL, helper, err)
+	}
+
+	return creds.Username, creds.Secret, nil
+}
+
+// RunCredentialHelper runs `docker-credential-<helper> get` according to the Docker credential helper protocol.
+func RunCredentialHelper(helper, serverURL string) ([]byte, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("%w: %s", err, strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// normalizeRegistryHost turns keys like `https://ghcr.io/v1/` into hosts like `ghcr.io`, so that they can be compared.
+// All the hosts of Docker Hub are normalized to `index.docker.io`.
+func normalizeRegistryHost(s string) string {
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+
+	if i := strings.Index(s, "/"); i >= 0 {
+		s = s[:i]
+	}
+
+	switch s {
+	case "docker.io", "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return "index.docker.io"
+	}
+
+	return s
+}
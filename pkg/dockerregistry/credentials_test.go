@@ -0,0 +1,102 @@
+package dockerregistry
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDockerConfig_Credentials(t *testing.T) {
+	conf, err := ParseDockerConfig([]byte(`{
+  "auths": {
+    "https://index.docker.io/v1/": {"auth": "aHVidXNlcjpodWJwYXNz"},
+    "ghcr.io": {"username": "ghuser", "password": "ghpass"},
+    "quay.io": {}
+  },
+  "credHelpers": {
+    "123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-login"
+  },
+  "credsStore": "desktop"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var helperCalls []string
+
+	conf.RunHelper = func(helper, serverURL string) ([]byte, error) {
+		helperCalls = append(helperCalls, helper+" "+serverURL)
+
+		switch helper + " " + serverURL {
+		case "ecr-login 123456789012.dkr.ecr.us-east-1.amazonaws.com":
+			return []byte(`{"ServerURL":"123456789012.dkr.ecr.us-east-1.amazonaws.com","Username":"AWS","Secret":"ecrpass"}`), nil
+		case "desktop https://index.docker.io/v1/":
+			return []byte(`{"ServerURL":"https://index.docker.io/v1/","Username":"desktopuser","Secret":"desktoppass"}`), nil
+		case "desktop quay.io":
+			return []byte(`{"ServerURL":"quay.io","Username":"quayuser","Secret":"quaypass"}`), nil
+		}
+
+		return []byte("credentials not found in native keychain\n"), errors.New("exit status 1")
+	}
+
+	type testcase struct {
+		host             string
+		username, secret string
+	}
+
+	check := func(testcases []testcase, expectedCalls []string) {
+		t.Helper()
+
+		helperCalls = nil
+
+		for i, tc := range testcases {
+			username, secret, err := conf.Credentials(tc.host)
+			if err != nil {
+				t.Fatalf("#%d: %v", i, err)
+			}
+
+			if username != tc.username || secret != tc.secret {
+				t.Errorf("#%d: unexpected credentials for %s: expected=%s:%s, got=%s:%s", i, tc.host, tc.username, tc.secret, username, secret)
+			}
+		}
+
+		if len(helperCalls) != len(expectedCalls) {
+			t.Fatalf("unexpected helper calls: %v", helperCalls)
+		}
+		for i := range expectedCalls {
+			if helperCalls[i] != expectedCalls[i] {
+				t.Errorf("unexpected helper call #%d: expected %s, got %s", i, expectedCalls[i], helperCalls[i])
+			}
+		}
+	}
+
+	// The credsStore takes precedence over auths, whose entries are left by `docker login` even when the credentials are in the store
+	check(
+		[]testcase{
+			{host: "registry.hub.docker.com", username: "desktopuser", secret: "desktoppass"},
+			{host: "ghcr.io"},
+			{host: "123456789012.dkr.ecr.us-east-1.amazonaws.com", username: "AWS", secret: "ecrpass"},
+			{host: "quay.io", username: "quayuser", secret: "quaypass"},
+		},
+		[]string{
+			"desktop https://index.docker.io/v1/",
+			"desktop ghcr.io",
+			"ecr-login 123456789012.dkr.ecr.us-east-1.amazonaws.com",
+			"desktop quay.io",
+		},
+	)
+
+	conf.CredsStore = ""
+
+	check(
+		[]testcase{
+			{host: "registry.hub.docker.com", username: "hubuser", secret: "hubpass"},
+			{host: "ghcr.io", username: "ghuser", secret: "ghpass"},
+			{host: "123456789012.dkr.ecr.us-east-1.amazonaws.com", username: "AWS", secret: "ecrpass"},
+			{host: "quay.io"},
+			{host: "registry.example.com"},
+		},
+		[]string{
+			"ecr-login 123456789012.dkr.ecr.us-east-1.amazonaws.com",
+		},
+	)
+}
@@ -0,0 +1,341 @@
+package dockerregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Media types of manifests supported by this client.
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+var manifestMediaTypes = []string{
+	MediaTypeDockerManifestList,
+	MediaTypeOCIIndex,
+	MediaTypeDockerManifest,
+	MediaTypeOCIManifest,
+}
+
+// Platform is the platform of an image listed in a manifest list or an OCI image index.
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ParsePlatform parses the platform in the form of `os/arch` or `os/arch/variant`, like `linux/arm64/v8`.
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Platform{}, fmt.Errorf("invalid platform %q: must be in the form of os/arch or os/arch/variant", s)
+	}
+
+	for _, p := range parts {
+		if p == "" {
+			return Platform{}, fmt.Errorf("invalid platform %q: must be in the form of os/arch or os/arch/variant", s)
+		}
+	}
+
+	p := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+
+	return p, nil
+}
+
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Satisfies returns true when the image for this platform runs on the wanted platform.
+// The variant is compared only when the wanted platform has one.
+func (p Platform) Satisfies(want Platform) bool {
+	if p.OS != want.OS || p.Architecture != want.Architecture {
+		return false
+	}
+
+	return want.Variant == "" || p.Variant == want.Variant
+}
+
+// Descriptor references a content-addressed blob or manifest.
+type Descriptor struct {
+	MediaType string    `json:"mediaType"`
+	Digest    string    `json:"digest"`
+	Size      int64     `json:"size"`
+	Platform  *Platform `json:"platform,omitempty"`
+}
+
+// Manifest is either an image manifest or a manifest list (OCI image index).
+// Config is set for image manifests, whereas Manifests is set for manifest lists.
+type Manifest struct {
+	MediaType string `json:"mediaType"`
+
+	// ArtifactType is the type of the artifact, like `application/vnd.cncf.helm.config.v1+json`, for OCI artifacts that set it.
+	// Otherwise the type of the artifact is told by the media type of the config.
+	ArtifactType string `json:"artifactType,omitempty"`
+
+	Config    *Descriptor  `json:"config,omitempty"`
+	Layers    []Descriptor `json:"layers,omitempty"`
+	Manifests []Descriptor `json:"manifests,omitempty"`
+
+	// Digest is the content digest of the manifest, as returned in the Docker-Content-Digest header or
+	// computed from the manifest content.
+	Digest string `json:"-"`
+}
+
+// IsList returns true when the manifest is a manifest list or an OCI image index.
+func (m *Manifest) IsList() bool {
+	return m.MediaType == MediaTypeDockerManifestList || m.MediaType == MediaTypeOCIIndex || len(m.Manifests) > 0
+}
+
+// Manifest fetches the manifest of the image referenced by the tag or the digest.
+// Manifests are cached, so that the same manifest is fetched only once per client.
+func (c *Client) Manifest(repository, reference string) (*Manifest, error) {
+	key := repository + "/" + reference
+	if m, ok := c.manifests[key]; ok {
+		return m, nil
+	}
+
+	m, err := c.fetchManifest(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	c.manifests[key] = m
+
+	return m, nil
+}
+
+func (c *Client) fetchManifest(repository, reference string) (*Manifest, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url("/v2/%s/manifests/%s", repository, reference), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching manifest %s:%s: unexpected status code: %d", repository, reference, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("decoding manifest %s:%s: %w", repository, reference, err)
+	}
+
+	if m.MediaType == "" {
+		m.MediaType = resp.Header.Get("Content-Type")
+	}
+
+	m.Digest = resp.Header.Get("Docker-Content-Digest")
+	if m.Digest == "" {
+		sum := sha256.Sum256(body)
+		m.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return &m, nil
+}
+
+// Digest returns the content digest of the manifest referenced by the tag, without downloading the manifest.
+// For a multi-platform image, this is the digest of the manifest list or the OCI image index,
+// so that `image:tag@digest` references the same set of images on every platform.
+func (c *Client) Digest(repository, reference string) (string, error) {
+	if m, ok := c.manifests[repository+"/"+reference]; ok {
+		return m.Digest, nil
+	}
+
+	req, err := http.NewRequest(http.MethodHead, c.url("/v2/%s/manifests/%s", repository, reference), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+			return d, nil
+		}
+	}
+
+	// Some registries don't support HEAD requests, or omit the digest header.
+	// Fall back to fetching the manifest, whose digest can be computed from the content.
+	m, err := c.Manifest(repository, reference)
+	if err != nil {
+		return "", err
+	}
+
+	return m.Digest, nil
+}
+
+// Blob fetches the content of the blob identified by the digest.
+func (c *Client) Blob(repository, digest string) ([]byte, error) {
+	resp, err := c.client.Get(c.url("/v2/%s/blobs/%s", repository, digest))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching blob %s@%s: unexpected status code: %d", repository, digest, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+type imageConfig struct {
+	Created *time.Time `json:"created"`
+
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant"`
+}
+
+// Config fetches the config blob of the image or the OCI artifact referenced by the tag or the digest.
+// Config blobs are cached like manifests.
+func (c *Client) Config(repository, reference string) ([]byte, error) {
+	m, err := c.Manifest(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Config == nil {
+		return nil, fmt.Errorf("manifest of %s:%s has no config", repository, reference)
+	}
+
+	return c.configBlob(repository, m.Config.Digest)
+}
+
+func (c *Client) configBlob(repository, digest string) ([]byte, error) {
+	key := repository + "@" + digest
+	if blob, ok := c.configs[key]; ok {
+		return blob, nil
+	}
+
+	blob, err := c.Blob(repository, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	c.configs[key] = blob
+
+	return blob, nil
+}
+
+// imageConfig fetches and decodes the image config blob identified by the digest
+func (c *Client) imageConfig(repository, digest string) (*imageConfig, error) {
+	blob, err := c.configBlob(repository, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var config imageConfig
+	if err := json.Unmarshal(blob, &config); err != nil {
+		return nil, fmt.Errorf("decoding image config %s@%s: %w", repository, digest, err)
+	}
+
+	return &config, nil
+}
+
+// Platforms returns the platforms supported by the image referenced by the tag or the digest.
+// For a multi-platform image, they are read from the manifest list or the OCI image index, excluding entries like attestations
+// whose platform is `unknown/unknown`. For a single-platform image, the platform is read from the image config.
+func (c *Client) Platforms(repository, reference string) ([]Platform, error) {
+	m, err := c.Manifest(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.IsList() {
+		var platforms []Platform
+		for _, d := range m.Manifests {
+			if p := d.Platform; p != nil && p.OS != "unknown" && p.Architecture != "unknown" {
+				platforms = append(platforms, *p)
+			}
+		}
+		return platforms, nil
+	}
+
+	if m.Config == nil {
+		return nil, fmt.Errorf("manifest of %s:%s has no config", repository, reference)
+	}
+
+	config, err := c.imageConfig(repository, m.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Platform{{OS: config.OS, Architecture: config.Architecture, Variant: config.Variant}}, nil
+}
+
+// ImageCreated returns the creation time of the image, recorded in the image config blob.
+// For a multi-platform image, the creation time of the linux/amd64 image, or the first image in the list, is returned.
+func (c *Client) ImageCreated(repository, reference string) (time.Time, error) {
+	m, err := c.Manifest(repository, reference)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if m.IsList() {
+		if len(m.Manifests) == 0 {
+			return time.Time{}, fmt.Errorf("manifest list of %s:%s is empty", repository, reference)
+		}
+
+		d := m.Manifests[0]
+		for _, candidate := range m.Manifests {
+			if p := candidate.Platform; p != nil && p.OS == "linux" && p.Architecture == "amd64" {
+				d = candidate
+				break
+			}
+		}
+
+		m, err = c.Manifest(repository, d.Digest)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if m.Config == nil {
+		return time.Time{}, fmt.Errorf("manifest of %s:%s has no config", repository, reference)
+	}
+
+	config, err := c.imageConfig(repository, m.Config.Digest)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if config.Created == nil {
+		return time.Time{}, fmt.Errorf("image config of %s:%s has no creation time", repository, reference)
+	}
+
+	return *config.Created, nil
+}
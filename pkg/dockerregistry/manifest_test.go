@@ -0,0 +1,257 @@
+package dockerregistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestImageCreated_ManifestList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myrepo/manifests/v1.0.0":
+			if r.Header.Get("Accept") == "" {
+				t.Errorf("expected Accept header to be set")
+			}
+			w.Header().Set("Content-Type", MediaTypeDockerManifestList)
+			w.Header().Set("Docker-Content-Digest", "sha256:list")
+			json.NewEncoder(w).Encode(Manifest{
+				MediaType: MediaTypeDockerManifestList,
+				Manifests: []Descriptor{
+					{MediaType: MediaTypeDockerManifest, Digest: "sha256:arm64", Platform: &Platform{OS: "linux", Architecture: "arm64"}},
+					{MediaType: MediaTypeDockerManifest, Digest: "sha256:amd64", Platform: &Platform{OS: "linux", Architecture: "amd64"}},
+				},
+			})
+		case "/v2/myrepo/manifests/sha256:amd64":
+			w.Header().Set("Content-Type", MediaTypeDockerManifest)
+			json.NewEncoder(w).Encode(Manifest{
+				MediaType: MediaTypeDockerManifest,
+				Config:    &Descriptor{Digest: "sha256:config"},
+			})
+		case "/v2/myrepo/blobs/sha256:config":
+			w.Write([]byte(`{"architecture":"amd64","created":"2020-01-02T03:04:05Z"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.ImageCreated("myrepo", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !created.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, created)
+	}
+}
+
+func TestManifest_DigestFromContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MediaTypeOCIManifest)
+		w.Write([]byte(`{"schemaVersion":2,"config":{"digest":"sha256:config"}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := client.Manifest("myrepo", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.MediaType != MediaTypeOCIManifest {
+		t.Errorf("expected media type %s, got %s", MediaTypeOCIManifest, m.MediaType)
+	}
+
+	if m.IsList() {
+		t.Error("expected an image manifest, got a list")
+	}
+
+	expected := "sha256:dfba8550bfd304fb35d6ad3ae1604215f22b1518dd8d8f21a38cdd3a7607690e"
+	if m.Digest != expected {
+		t.Errorf("expected digest %s, got %s", expected, m.Digest)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	var methods []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/v2/myrepo/manifests/v1.0.0":
+			w.Header().Set("Content-Type", MediaTypeOCIIndex)
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+		case "/v2/myrepo/manifests/v2.0.0":
+			// The digest header is missing, so that the client needs to compute it from the content
+			w.Header().Set("Content-Type", MediaTypeOCIManifest)
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"schemaVersion":2,"config":{"digest":"sha256:config"}}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := client.Digest("myrepo", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d != "sha256:index" {
+		t.Errorf("expected digest sha256:index, got %s", d)
+	}
+
+	d, err = client.Digest("myrepo", "v2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "sha256:dfba8550bfd304fb35d6ad3ae1604215f22b1518dd8d8f21a38cdd3a7607690e"
+	if d != expected {
+		t.Errorf("expected digest %s, got %s", expected, d)
+	}
+
+	expectedMethods := []string{
+		"HEAD /v2/myrepo/manifests/v1.0.0",
+		"HEAD /v2/myrepo/manifests/v2.0.0",
+		"GET /v2/myrepo/manifests/v2.0.0",
+	}
+	if len(methods) != len(expectedMethods) {
+		t.Fatalf("unexpected requests: %v", methods)
+	}
+	for i := range methods {
+		if methods[i] != expectedMethods[i] {
+			t.Errorf("unexpected request #%d: expected %s, got %s", i, expectedMethods[i], methods[i])
+		}
+	}
+
+	if _, err := client.Digest("myrepo", "missing"); err == nil {
+		t.Error("expected error for a missing tag")
+	}
+}
+
+func TestPlatforms(t *testing.T) {
+	requests := map[string]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/v2/myrepo/manifests/v1.0.0":
+			w.Header().Set("Content-Type", MediaTypeOCIIndex)
+			json.NewEncoder(w).Encode(Manifest{
+				MediaType: MediaTypeOCIIndex,
+				Manifests: []Descriptor{
+					{MediaType: MediaTypeOCIManifest, Digest: "sha256:amd64", Platform: &Platform{OS: "linux", Architecture: "amd64"}},
+					{MediaType: MediaTypeOCIManifest, Digest: "sha256:arm64", Platform: &Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+					{MediaType: MediaTypeOCIManifest, Digest: "sha256:attestation", Platform: &Platform{OS: "unknown", Architecture: "unknown"}},
+				},
+			})
+		case "/v2/myrepo/manifests/v2.0.0":
+			w.Header().Set("Content-Type", MediaTypeDockerManifest)
+			json.NewEncoder(w).Encode(Manifest{
+				MediaType: MediaTypeDockerManifest,
+				Config:    &Descriptor{Digest: "sha256:config"},
+			})
+		case "/v2/myrepo/blobs/sha256:config":
+			w.Write([]byte(`{"architecture":"amd64","os":"linux","created":"2020-01-02T03:04:05Z"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		reference string
+		expected  []string
+	}{
+		{reference: "v1.0.0", expected: []string{"linux/amd64", "linux/arm64/v8"}},
+		{reference: "v2.0.0", expected: []string{"linux/amd64"}},
+	}
+
+	for i, tc := range testcases {
+		platforms, err := client.Platforms("myrepo", tc.reference)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		var actual []string
+		for _, p := range platforms {
+			actual = append(actual, p.String())
+		}
+
+		if len(actual) != len(tc.expected) {
+			t.Fatalf("#%d: expected %v, got %v", i, tc.expected, actual)
+		}
+		for j := range actual {
+			if actual[j] != tc.expected[j] {
+				t.Errorf("#%d: expected %v, got %v", i, tc.expected, actual)
+			}
+		}
+	}
+
+	// Subsequent requests for the same image are served from the cache
+	if _, err := client.Platforms("myrepo", "v2.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ImageCreated("myrepo", "v2.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Digest("myrepo", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("expected %s to be requested once, but requested %d times", path, n)
+		}
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	p, err := ParsePlatform("linux/arm64/v8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.Satisfies(Platform{OS: "linux", Architecture: "arm64"}) {
+		t.Errorf("expected %s to satisfy linux/arm64", p)
+	}
+
+	if (Platform{OS: "linux", Architecture: "arm", Variant: "v6"}).Satisfies(Platform{OS: "linux", Architecture: "arm", Variant: "v7"}) {
+		t.Error("expected linux/arm/v6 not to satisfy linux/arm/v7")
+	}
+
+	for _, invalid := range []string{"linux", "linux/", "linux/arm/v7/extra"} {
+		if _, err := ParsePlatform(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 // TokenTransport is an http.RoundTripper that handles Docker Registry token authentication.
 // When a request receives a 401 with a WWW-Authenticate header, it fetches a token
 // from the auth service and retries the request with the bearer token.
+// Registries like AWS ECR demand basic authentication instead, in which case the request is retried with the credentials.
 type TokenTransport struct {
 	Transport http.RoundTripper
 	Username  string
@@ -32,6 +33,11 @@ func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if authService := isTokenDemand(resp); authService != nil {
 		resp.Body.Close()
 		resp, err = t.authAndRetry(authService, req)
+	} else if isBasicDemand(resp) && (t.Username != "" || t.Password != "") {
+		resp.Body.Close()
+		retry := req.Clone(req.Context())
+		retry.SetBasicAuth(t.Username, t.Password)
+		resp, err = t.Transport.RoundTrip(retry)
 	}
 	return resp, err
 }
@@ -124,6 +130,18 @@ func isTokenDemand(resp *http.Response) *authService {
 	return parseOauthHeader(resp)
 }
 
+func isBasicDemand(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	for _, challenge := range parseAuthHeader(resp.Header) {
+		if challenge.Scheme == "basic" {
+			return true
+		}
+	}
+	return false
+}
+
 func parseOauthHeader(resp *http.Response) *authService {
 	challenges := parseAuthHeader(resp.Header)
 	for _, challenge := range challenges {
@@ -0,0 +1,187 @@
+// Package overlayfs provides a copy-on-write vfs.FS that keeps every write in memory.
+//
+// Reads fall through to the underlying filesystem unless the path has been written to the overlay,
+// so that a series of provisioners can see each other's results without touching the underlying filesystem.
+// Any other modification to the underlying filesystem is rejected with a permission error.
+package overlayfs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/twpayne/go-vfs"
+)
+
+type FS struct {
+	vfs.FS
+
+	files map[string]*file
+	dirs  map[string]os.FileMode
+}
+
+type file struct {
+	data []byte
+	mode os.FileMode
+}
+
+// Change is a file written to the overlay, along with the content of the same file in the underlying filesystem.
+type Change struct {
+	Path string
+
+	// Existed is true when the file existed in the underlying filesystem
+	Existed bool
+
+	Before []byte
+	After  []byte
+}
+
+var _ vfs.FS = &FS{}
+
+func New(base vfs.FS) *FS {
+	return &FS{
+		FS:    vfs.NewReadOnlyFS(base),
+		files: map[string]*file{},
+		dirs:  map[string]os.FileMode{},
+	}
+}
+
+func (o *FS) ReadFile(filename string) ([]byte, error) {
+	if f, ok := o.files[filepath.Clean(filename)]; ok {
+		return append([]byte{}, f.data...), nil
+	}
+	return o.FS.ReadFile(filename)
+}
+
+func (o *FS) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	name := filepath.Clean(filename)
+	if _, ok := o.dirs[name]; ok {
+		return &os.PathError{Op: "open", Path: filename, Err: syscall.EISDIR}
+	}
+	o.files[name] = &file{data: append([]byte{}, data...), mode: perm}
+	return nil
+}
+
+func (o *FS) Mkdir(name string, perm os.FileMode) error {
+	n := filepath.Clean(name)
+	if _, err := o.Stat(n); err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+	parent, err := o.Stat(filepath.Dir(n))
+	if err != nil || !parent.IsDir() {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrNotExist}
+	}
+	o.dirs[n] = perm
+	return nil
+}
+
+func (o *FS) Stat(name string) (os.FileInfo, error) {
+	if info, ok := o.stat(name); ok {
+		return info, nil
+	}
+	return o.FS.Stat(name)
+}
+
+func (o *FS) Lstat(name string) (os.FileInfo, error) {
+	if info, ok := o.stat(name); ok {
+		return info, nil
+	}
+	return o.FS.Lstat(name)
+}
+
+func (o *FS) stat(name string) (os.FileInfo, bool) {
+	n := filepath.Clean(name)
+	if f, ok := o.files[n]; ok {
+		return &fileInfo{name: filepath.Base(n), size: int64(len(f.data)), mode: f.mode}, true
+	}
+	if perm, ok := o.dirs[n]; ok {
+		return &fileInfo{name: filepath.Base(n), mode: os.ModeDir | perm}, true
+	}
+	return nil, false
+}
+
+// ReadDir returns entries of the underlying directory merged with the ones created in the overlay
+func (o *FS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	dir := filepath.Clean(dirname)
+
+	byName := map[string]os.FileInfo{}
+
+	infos, err := o.FS.ReadDir(dirname)
+	if err != nil {
+		if _, ok := o.dirs[dir]; !ok {
+			return nil, err
+		}
+	}
+	for _, info := range infos {
+		byName[info.Name()] = info
+	}
+
+	for n := range o.dirs {
+		if filepath.Dir(n) == dir && n != dir {
+			info, _ := o.stat(n)
+			byName[info.Name()] = info
+		}
+	}
+	for n := range o.files {
+		if filepath.Dir(n) == dir {
+			info, _ := o.stat(n)
+			byName[info.Name()] = info
+		}
+	}
+
+	res := make([]os.FileInfo, 0, len(byName))
+	for _, info := range byName {
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res, nil
+}
+
+// Changes returns all the files written to the overlay, sorted by path
+func (o *FS) Changes() ([]Change, error) {
+	var paths []string
+	for p := range o.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var changes []Change
+	for _, p := range paths {
+		c := Change{
+			Path:  p,
+			After: o.files[p].data,
+		}
+
+		before, err := o.FS.ReadFile(p)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			c.Existed = true
+			c.Before = before
+		}
+
+		changes = append(changes, c)
+	}
+
+	return changes, nil
+}
+
+type fileInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() os.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return time.Time{} }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,86 @@
+package overlayfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs"
+	"github.com/twpayne/go-vfs/vfst"
+)
+
+func TestOverlay(t *testing.T) {
+	base, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/path/to/existing.txt": "foo\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	o := New(base)
+
+	if err := o.WriteFile("/path/to/existing.txt", []byte("bar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vfs.MkdirAll(o, "/path/to/new/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.WriteFile("/path/to/new/dir/added.txt", []byte("baz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := o.ReadFile("/path/to/existing.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "bar\n" {
+		t.Errorf("unexpected content read from overlay: %q", string(got))
+	}
+
+	var walked []string
+	if err := vfs.Walk(o, "/path/to", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			walked = append(walked, path)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"/path/to/existing.txt", "/path/to/new/dir/added.txt"}, walked); d != "" {
+		t.Errorf("unexpected walked files: %s", d)
+	}
+
+	orig, err := base.ReadFile("/path/to/existing.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(orig) != "foo\n" {
+		t.Errorf("underlying file must not be modified: %q", string(orig))
+	}
+	if _, err := base.Stat("/path/to/new"); !os.IsNotExist(err) {
+		t.Errorf("underlying directory must not be created: %v", err)
+	}
+
+	if err := o.Remove("/path/to/existing.txt"); err == nil {
+		t.Errorf("expected error on removing underlying file")
+	}
+
+	changes, err := o.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		{Path: "/path/to/existing.txt", Existed: true, Before: []byte("foo\n"), After: []byte("bar\n")},
+		{Path: "/path/to/new/dir/added.txt", After: []byte("baz\n")},
+	}
+	if d := cmp.Diff(expected, changes); d != "" {
+		t.Errorf("unexpected changes: %s", d)
+	}
+}
@@ -0,0 +1,60 @@
+package releasetracker
+
+import (
+	"fmt"
+	"os"
+)
+
+// GiteaReleases tracks releases of a repository in Gitea, or Forgejo which serves the same API
+type GiteaReleases struct {
+	// Host is the hostname of the instance like `gitea.example.com` or `codeberg.org`
+	Host string `yaml:"host"`
+	// Source is the repository like `OWNER/REPO`
+	Source string `yaml:"source"`
+	// Token is the access token for private repositories. Defaults to GITEA_TOKEN
+	Token string `yaml:"token"`
+
+	// IncludePrereleases includes releases flagged as `prerelease` by Gitea API
+	IncludePrereleases bool `yaml:"includePrereleases"`
+	// IncludeDrafts includes releases flagged as `draft` by Gitea API
+	IncludeDrafts bool `yaml:"includeDrafts"`
+}
+
+// giteaPaginationParams requests 50 items per page, the default of MAX_RESPONSE_ITEMS in Gitea and Forgejo
+var giteaPaginationParams = map[string]string{"limit": "50"}
+
+func newGiteaReleasesProvider(spec GiteaReleases, r *Tracker) *httpJsonPathProvider {
+	url := fmt.Sprintf("https://%s/api/v1/repos/%s/releases", spec.Host, spec.Source)
+
+	var auth string
+
+	token := spec.Token
+	if token == "" {
+		token = os.Getenv("GITEA_TOKEN")
+	}
+	if token != "" {
+		auth = "token " + token
+	}
+
+	var excludeFlags []string
+	if !spec.IncludePrereleases {
+		excludeFlags = append(excludeFlags, "prerelease")
+	}
+	if !spec.IncludeDrafts {
+		excludeFlags = append(excludeFlags, "draft")
+	}
+
+	return &httpJsonPathProvider{
+		url:           url,
+		authorization: auth,
+		excludeFlags:  excludeFlags,
+		jsonpath:      "$[*].tag_name",
+		metaKey:       "giteaRelease",
+		objectPath:    "$[*]",
+		versionPath:   "tag_name",
+		timePath:      "published_at",
+		params:        giteaPaginationParams,
+		followLinks:   true,
+		runtime:       r,
+	}
+}
@@ -0,0 +1,127 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_GiteaReleases(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "secret")
+
+	var requests []string
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+
+		if a := r.Header.Get("Authorization"); a != "token secret" {
+			t.Errorf("unexpected authorization: %q", a)
+		}
+
+		var releases []map[string]interface{}
+
+		switch r.URL.RequestURI() {
+		case "/api/v1/repos/myorg/mytool/releases?limit=50":
+			releases = []map[string]interface{}{
+				{"tag_name": "v1.3.0", "draft": true, "prerelease": false, "published_at": "2024-03-01T00:00:00Z"},
+				{"tag_name": "v1.3.0-rc.1", "draft": false, "prerelease": true, "published_at": "2024-02-15T00:00:00Z"},
+				{"tag_name": "v1.2.0", "draft": false, "prerelease": false, "published_at": "2024-02-01T00:00:00Z", "body": "Changelog", "assets": []interface{}{
+					map[string]interface{}{"name": "mytool_linux_amd64.tar.gz", "browser_download_url": "https://gitea.example.com/myorg/mytool/releases/download/v1.2.0/mytool_linux_amd64.tar.gz"},
+				}},
+			}
+			// Gitea tells the next page with the Link header, along with X-Total-Count
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/repos/myorg/mytool/releases?limit=50&page=2>; rel="next",<%s/api/v1/repos/myorg/mytool/releases?limit=50&page=2>; rel="last"`, server.URL, server.URL))
+			w.Header().Set("X-Total-Count", "4")
+		case "/api/v1/repos/myorg/mytool/releases?limit=50&page=2":
+			releases = []map[string]interface{}{
+				{"tag_name": "v1.1.0", "draft": false, "prerelease": false, "published_at": "2024-01-01T00:00:00Z"},
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v1/repos/myorg/mytool/releases?limit=50&page=1>; rel="first",<%s/api/v1/repos/myorg/mytool/releases?limit=50&page=1>; rel="prev"`, server.URL, server.URL))
+			w.Header().Set("X-Total-Count", "4")
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	host := server.URL[len("https://"):]
+
+	testcases := []struct {
+		spec     GiteaReleases
+		expected []string
+	}{
+		{
+			spec:     GiteaReleases{Host: host, Source: "myorg/mytool"},
+			expected: []string{"1.1.0", "1.2.0"},
+		},
+		{
+			spec:     GiteaReleases{Host: host, Source: "myorg/mytool", Token: "secret", IncludePrereleases: true, IncludeDrafts: true},
+			expected: []string{"1.1.0", "1.2.0", "1.3.0-rc.1", "1.3.0"},
+		},
+	}
+
+	for i, tc := range testcases {
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{GiteaReleases: tc.spec}}, HttpGetter(vhttpget.NewWithClient(server.Client())))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		releases, err := tracker.GetReleases()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		var actual []string
+		for _, r := range releases {
+			actual = append(actual, r.Version)
+		}
+
+		if d := cmp.Diff(tc.expected, actual); d != "" {
+			t.Errorf("#%d: unexpected versions: %s", i, d)
+		}
+	}
+
+	tracker, err := New(Spec{VersionsFrom: VersionsFrom{GiteaReleases: testcases[0].spec}}, HttpGetter(vhttpget.NewWithClient(server.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !latest.PublishedAt.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected publication time: %v", latest.PublishedAt)
+	}
+
+	meta, ok := latest.Meta["giteaRelease"].(map[string]interface{})
+	if !ok || meta["body"] != "Changelog" {
+		t.Errorf("unexpected giteaRelease in meta: %v", latest.Meta)
+	}
+
+	if len(requests) != 6 {
+		t.Errorf("expected 2 requests per listing, got %v", requests)
+	}
+
+	noHost, err := New(Spec{VersionsFrom: VersionsFrom{GiteaReleases: GiteaReleases{Source: "myorg/mytool"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := noHost.GetReleases(); err == nil {
+		t.Error("expected error for missing host")
+	}
+}
@@ -0,0 +1,75 @@
+package releasetracker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GitLabReleases tracks releases of a GitLab project
+type GitLabReleases struct {
+	// BaseURL is the URL of the GitLab instance like `https://gitlab.example.com`. Defaults to https://gitlab.com
+	BaseURL string `yaml:"baseURL"`
+	// Source is the path of the project like `group/subgroup/project`
+	Source string `yaml:"source"`
+}
+
+// GitLabTags tracks tags of a GitLab project
+type GitLabTags struct {
+	// BaseURL is the URL of the GitLab instance like `https://gitlab.example.com`. Defaults to https://gitlab.com
+	BaseURL string `yaml:"baseURL"`
+	// Source is the path of the project like `group/subgroup/project`
+	Source string `yaml:"source"`
+}
+
+const defaultGitLabBaseURL = "https://gitlab.com"
+
+// gitLabProjectURL returns the URL of the project in GitLab API, where the project path is URL-encoded as the project ID
+func gitLabProjectURL(baseURL, source string) string {
+	if baseURL == "" {
+		baseURL = defaultGitLabBaseURL
+	}
+
+	return fmt.Sprintf("%s/api/v4/projects/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(source))
+}
+
+// gitLabHeader returns the header to authenticate with the personal, group, or project access token in GITLAB_TOKEN
+func gitLabHeader() map[string]string {
+	header := map[string]string{}
+
+	if t := os.Getenv("GITLAB_TOKEN"); t != "" {
+		header["PRIVATE-TOKEN"] = t
+	}
+
+	return header
+}
+
+func newGitLabReleasesProvider(spec GitLabReleases, r *Tracker) *httpJsonPathProvider {
+	return &httpJsonPathProvider{
+		url:    gitLabProjectURL(spec.BaseURL, spec.Source) + "/releases",
+		header: gitLabHeader(),
+		// Upcoming releases are the ones whose released_at is in the future
+		excludeFlags: []string{"upcoming_release"},
+		jsonpath:     "$[*].tag_name",
+		metaKey:      "gitlabRelease",
+		objectPath:   "$[*]",
+		versionPath:  "tag_name",
+		timePath:     "released_at",
+		params:       map[string]string{"per_page": "100"},
+		followLinks:  true,
+		runtime:      r,
+	}
+}
+
+func newGitLabTagsProvider(spec GitLabTags, r *Tracker) *httpJsonPathProvider {
+	return &httpJsonPathProvider{
+		url:      gitLabProjectURL(spec.BaseURL, spec.Source) + "/repository/tags",
+		header:   gitLabHeader(),
+		jsonpath: "$[*].name",
+		// Keyset pagination, whose Link header points to the next page, doesn't slow down for projects with many tags
+		params:      map[string]string{"per_page": "100", "pagination": "keyset", "order_by": "name", "sort": "desc"},
+		followLinks: true,
+		runtime:     r,
+	}
+}
@@ -0,0 +1,127 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_GitLab(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	var requests []string
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+
+		if tok := r.Header.Get("PRIVATE-TOKEN"); tok != "secret" {
+			t.Errorf("unexpected token: %q", tok)
+		}
+
+		var body interface{}
+
+		switch r.URL.RequestURI() {
+		case "/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/releases?per_page=100":
+			body = []map[string]interface{}{
+				{"tag_name": "v1.2.0", "released_at": "2030-01-01T00:00:00.000Z", "upcoming_release": true},
+				{
+					"tag_name":         "v1.1.0",
+					"description":      "Bug fixes",
+					"released_at":      "2024-02-01T10:00:00.000Z",
+					"upcoming_release": false,
+					"assets": map[string]interface{}{
+						"links": []interface{}{map[string]interface{}{"name": "myproject_linux_amd64.tar.gz", "url": "https://example.com/myproject_linux_amd64.tar.gz"}},
+					},
+				},
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/gitlab/api/v4/projects/mygroup%%2Fsub%%2Fmyproject/releases?page=2&per_page=100>; rel="next"`, server.URL))
+		case "/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/releases?page=2&per_page=100":
+			body = []map[string]interface{}{
+				{"tag_name": "v1.0.0", "released_at": "2024-01-01T10:00:00.000Z", "upcoming_release": false},
+			}
+		case "/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/repository/tags?order_by=name&pagination=keyset&per_page=100&sort=desc":
+			body = []map[string]interface{}{{"name": "v1.2.0"}, {"name": "v1.1.0"}}
+			// The keyset pagination tells the next page only with the Link header
+			w.Header().Set("Link", fmt.Sprintf(`<%s/gitlab/api/v4/projects/mygroup%%2Fsub%%2Fmyproject/repository/tags?order_by=name&page_token=v1.1.0&pagination=keyset&per_page=100&sort=desc>; rel="next"`, server.URL))
+		case "/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/repository/tags?order_by=name&page_token=v1.1.0&pagination=keyset&per_page=100&sort=desc":
+			body = []map[string]interface{}{{"name": "v1.0.0"}, {"name": "latest"}}
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	httpGetter := HttpGetter(vhttpget.NewWithClient(server.Client()))
+
+	releasesTracker, err := New(Spec{VersionsFrom: VersionsFrom{GitLabReleases: GitLabReleases{BaseURL: server.URL + "/gitlab/", Source: "mygroup/sub/myproject"}}}, httpGetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := releasesTracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.1.0" || latest.Tag != "v1.1.0" {
+		t.Errorf("unexpected release: %+v", latest)
+	}
+
+	if !latest.PublishedAt.Equal(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected publication time: %v", latest.PublishedAt)
+	}
+
+	meta, ok := latest.Meta["gitlabRelease"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing gitlabRelease in meta: %v", latest.Meta)
+	}
+
+	if meta["description"] != "Bug fixes" || meta["released_at"] != "2024-02-01T10:00:00.000Z" {
+		t.Errorf("unexpected gitlabRelease: %v", meta)
+	}
+
+	if _, ok := meta["assets"].(map[string]interface{}); !ok {
+		t.Errorf("missing assets in gitlabRelease: %v", meta)
+	}
+
+	tagsTracker, err := New(Spec{VersionsFrom: VersionsFrom{GitLabTags: GitLabTags{BaseURL: server.URL + "/gitlab", Source: "mygroup/sub/myproject"}}}, httpGetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := tagsTracker.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, r := range tags {
+		actual = append(actual, r.Version)
+	}
+
+	if d := cmp.Diff([]string{"1.0.0", "1.1.0", "1.2.0"}, actual); d != "" {
+		t.Errorf("unexpected tags: %s", d)
+	}
+
+	expectedRequests := []string{
+		"/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/releases?per_page=100",
+		"/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/releases?page=2&per_page=100",
+		"/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/repository/tags?order_by=name&pagination=keyset&per_page=100&sort=desc",
+		"/gitlab/api/v4/projects/mygroup%2Fsub%2Fmyproject/repository/tags?order_by=name&page_token=v1.1.0&pagination=keyset&per_page=100&sort=desc",
+	}
+	if d := cmp.Diff(expectedRequests, requests); d != "" {
+		t.Errorf("unexpected requests: %s", d)
+	}
+}
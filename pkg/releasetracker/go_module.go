@@ -0,0 +1,273 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+// GoModule tracks versions of a Go module via the module proxy protocol
+type GoModule struct {
+	// Path is the module path like `github.com/BurntSushi/toml`
+	Path string `yaml:"path"`
+
+	// IncludeIncompatible includes `+incompatible` versions, which are major versions v2 or later of modules without
+	// the major version suffix in the path. They are skipped by default, like `go get` does for modules with go.mod.
+	IncludeIncompatible bool `yaml:"includeIncompatible"`
+}
+
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+type goModuleProvider struct {
+	path                string
+	includeIncompatible bool
+
+	runtime *Tracker
+}
+
+func newGoModuleProvider(spec GoModule, r *Tracker) *goModuleProvider {
+	return &goModuleProvider{
+		path:                spec.Path,
+		includeIncompatible: spec.IncludeIncompatible,
+		runtime:             r,
+	}
+}
+
+var _ ReleaseProvider = &goModuleProvider{}
+
+// goModuleInfo is the response of `$GOPROXY/<module>/@v/<version>.info` and `$GOPROXY/<module>/@latest`
+type goModuleInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// All returns the versions in `$GOPROXY/<module>/@v/list`.
+// When the module has no tagged versions, the pseudo-version from `$GOPROXY/<module>/@latest` is returned instead.
+func (p *goModuleProvider) All() ([]*Release, error) {
+	body, err := p.fetch("@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, v := range strings.Fields(body) {
+		if strings.HasSuffix(v, "+incompatible") && !p.includeIncompatible {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	if len(strings.Fields(body)) == 0 {
+		info, err := p.info("@latest")
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, info.Version)
+	}
+
+	return p.runtime.versionsToReleases(versions)
+}
+
+var _ publicationTimeGetter = &goModuleProvider{}
+
+// PublishedAt returns the commit time of the version recorded in `$GOPROXY/<module>/@v/<version>.info`
+func (p *goModuleProvider) PublishedAt(r *Release) (time.Time, error) {
+	version, err := escapeGoModulePath(r.OriginalTag())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	info, err := p.info("@v/" + version + ".info")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.Time, nil
+}
+
+func (p *goModuleProvider) info(suffix string) (*goModuleInfo, error) {
+	body, err := p.fetch(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var info goModuleInfo
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		return nil, fmt.Errorf("decoding %s of module %s: %w", suffix, p.path, err)
+	}
+
+	return &info, nil
+}
+
+// errGoProxyNotFound tells that the proxy doesn't have the module, so that the next proxy in GOPROXY can be tried
+var errGoProxyNotFound = errors.New("not found")
+
+// fetch gets `<module>/<suffix>` from the proxies in GOPROXY in order, as the go command does.
+// The next proxy is tried only when the previous one responded with 404 or 410 for a comma-separated proxy,
+// or on any error for a pipe-separated proxy.
+func (p *goModuleProvider) fetch(suffix string) (string, error) {
+	if p.path == "" {
+		return "", fmt.Errorf("path is required for goModule")
+	}
+
+	escaped, err := escapeGoModulePath(p.path)
+	if err != nil {
+		return "", err
+	}
+
+	goproxy := os.Getenv("GOPROXY")
+	if goproxy == "" {
+		goproxy = defaultGoProxy
+	}
+
+	noproxy := os.Getenv("GONOPROXY")
+	if noproxy == "" {
+		noproxy = os.Getenv("GOPRIVATE")
+	}
+
+	if matchGoPrefixPatterns(noproxy, p.path) {
+		return "", fmt.Errorf("module %s matches GONOPROXY or GOPRIVATE, but fetching modules directly from version control systems is not supported: set GONOPROXY to exclude it and GOPROXY to a proxy serving it", p.path)
+	}
+
+	var errs []string
+
+	for _, proxy := range parseGoProxy(goproxy) {
+		switch proxy.url {
+		case "off":
+			errs = append(errs, "module lookup disabled by GOPROXY=off")
+			return "", fmt.Errorf("fetching %s of module %s: %s", suffix, p.path, strings.Join(errs, "; "))
+		case "direct":
+			errs = append(errs, "direct: fetching modules directly from version control systems is not supported")
+			continue
+		}
+
+		body, err := p.fetchFrom(proxy.url, escaped+"/"+suffix)
+		if err == nil {
+			return body, nil
+		}
+
+		errs = append(errs, err.Error())
+
+		if !proxy.fallbackOnError && !errors.Is(err, errGoProxyNotFound) {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("fetching %s of module %s: %s", suffix, p.path, strings.Join(errs, "; "))
+}
+
+func (p *goModuleProvider) fetchFrom(proxy, rel string) (string, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return "", fmt.Errorf("parsing GOPROXY entry %q: %w", proxy, err)
+	}
+
+	if u.Scheme == "file" {
+		file := path.Join(u.Path, rel)
+
+		data, err := p.runtime.fs.ReadFile(file)
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s: %w", file, errGoProxyNotFound)
+		} else if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+
+	res, err := p.runtime.httpGetter.Get(strings.TrimSuffix(proxy, "/") + "/" + rel)
+	if err != nil {
+		var se *vhttpget.StatusError
+		if errors.As(err, &se) && (se.StatusCode == http.StatusNotFound || se.StatusCode == http.StatusGone) {
+			return "", fmt.Errorf("%v: %w", err, errGoProxyNotFound)
+		}
+		return "", err
+	}
+
+	return res.Body, nil
+}
+
+type goProxyEntry struct {
+	url string
+
+	// fallbackOnError is true when the entry is followed by `|`, which means the next entry is tried on any error
+	fallbackOnError bool
+}
+
+func parseGoProxy(goproxy string) []goProxyEntry {
+	var entries []goProxyEntry
+
+	for goproxy != "" {
+		i := strings.IndexAny(goproxy, ",|")
+
+		var e goProxyEntry
+		if i < 0 {
+			e.url = goproxy
+			goproxy = ""
+		} else {
+			e.url = goproxy[:i]
+			e.fallbackOnError = goproxy[i] == '|'
+			goproxy = goproxy[i+1:]
+		}
+
+		if e.url = strings.TrimSpace(e.url); e.url != "" {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// matchGoPrefixPatterns reports whether any of the comma-separated glob patterns, like the ones in GOPRIVATE,
+// matches the leading path elements of the module path.
+func matchGoPrefixPatterns(patterns, target string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSuffix(strings.TrimSpace(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+
+		n := strings.Count(pattern, "/") + 1
+		elems := strings.Split(target, "/")
+		if len(elems) < n {
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, strings.Join(elems[:n], "/")); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapeGoModulePath case-encodes the module path or the version for the module proxy protocol,
+// by replacing every uppercase letter with an exclamation mark followed by the lowercase letter.
+func escapeGoModulePath(s string) (string, error) {
+	var b strings.Builder
+
+	for _, r := range s {
+		if r == '!' || r >= unicode.MaxASCII {
+			return "", fmt.Errorf("invalid character %q in %q", r, s)
+		}
+
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String(), nil
+}
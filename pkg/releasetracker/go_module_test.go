@@ -0,0 +1,99 @@
+package releasetracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_GoModule(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/proxy/github.com/!burnt!sushi/toml/@v/list":        "v0.4.1\nv1.2.0\nv1.3.0\nv2.0.0+incompatible\n",
+		"/path/to/proxy/github.com/!burnt!sushi/toml/@v/v1.3.0.info": `{"Version":"v1.3.0","Time":"2024-01-10T00:00:00Z"}`,
+		"/path/to/proxy/github.com/!burnt!sushi/toml/@v/v1.2.0.info": `{"Version":"v1.2.0","Time":"2023-01-10T00:00:00Z"}`,
+		"/path/to/proxy/example.com/untagged/@v/list":                "",
+		"/path/to/proxy/example.com/untagged/@latest":                `{"Version":"v0.0.0-20240101000000-abcdefabcdef","Time":"2024-01-01T00:00:00Z"}`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	// The first proxy doesn't have the modules, so that the go command falls back to the next one
+	t.Setenv("GOPROXY", "file:///path/to/empty,file:///path/to/proxy")
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GOPRIVATE", "")
+
+	testcases := []struct {
+		spec        GoModule
+		minAge      time.Duration
+		expected    string
+		expectedTag string
+	}{
+		{spec: GoModule{Path: "github.com/BurntSushi/toml"}, expected: "1.3.0", expectedTag: "v1.3.0"},
+		{spec: GoModule{Path: "github.com/BurntSushi/toml", IncludeIncompatible: true}, expected: "2.0.0+incompatible", expectedTag: "v2.0.0+incompatible"},
+		// v1.3.0 was published 7 days before now according to the .info
+		{spec: GoModule{Path: "github.com/BurntSushi/toml"}, minAge: 30 * 24 * time.Hour, expected: "1.2.0", expectedTag: "v1.2.0"},
+		{spec: GoModule{Path: "example.com/untagged"}, expected: "0.0.0-20240101000000-abcdefabcdef", expectedTag: "v0.0.0-20240101000000-abcdefabcdef"},
+	}
+
+	for i, tc := range testcases {
+		spec := Spec{VersionsFrom: VersionsFrom{GoModule: tc.spec}, MinimumReleaseAge: tc.minAge}
+
+		tracker, err := New(spec, FS(fs), Now(func() time.Time { return time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC) }))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest("")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if latest.Version != tc.expected || latest.OriginalTag() != tc.expectedTag {
+			t.Errorf("#%d: unexpected release: expected=%s (%s), got=%s (%s)", i, tc.expected, tc.expectedTag, latest.Version, latest.OriginalTag())
+		}
+	}
+}
+
+func TestProvider_GoModule_Proxies(t *testing.T) {
+	httpGetter := vhttpget.NewTester(map[string]string{
+		"https://goproxy.example.com/github.com/!burnt!sushi/toml/@v/list": "v1.3.0\n",
+	})
+
+	testcases := []struct {
+		goproxy, goprivate, gonoproxy string
+		ok                            bool
+	}{
+		{goproxy: "https://goproxy.example.com", ok: true},
+		{goproxy: "off"},
+		{goproxy: "direct"},
+		// The tester fails with an error other than 404 for the unknown URL, which falls back to the next proxy only with `|`
+		{goproxy: "https://unknown.example.com,https://goproxy.example.com"},
+		{goproxy: "https://unknown.example.com|https://goproxy.example.com", ok: true},
+		{goproxy: "https://goproxy.example.com", goprivate: "github.com/BurntSushi"},
+		{goproxy: "https://goproxy.example.com", goprivate: "*.example.com,github.com/*/toml"},
+		{goproxy: "https://goproxy.example.com", goprivate: "github.com/BurntSushi", gonoproxy: "none.example.com", ok: true},
+	}
+
+	for i, tc := range testcases {
+		t.Setenv("GOPROXY", tc.goproxy)
+		t.Setenv("GOPRIVATE", tc.goprivate)
+		t.Setenv("GONOPROXY", tc.gonoproxy)
+
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{GoModule: GoModule{Path: "github.com/BurntSushi/toml"}}}, HttpGetter(httpGetter))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tracker.Latest("")
+		if tc.ok && err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		} else if !tc.ok && err == nil {
+			t.Errorf("#%d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package releasetracker
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HelmChart tracks versions of a chart in a classic Helm chart repository that serves `index.yaml`
+type HelmChart struct {
+	// Repository is the URL of the chart repository, like `https://charts.helm.sh/stable`
+	Repository string `yaml:"repository"`
+	// Chart is the name of the chart in the repository
+	Chart string `yaml:"chart"`
+}
+
+type helmChartProvider struct {
+	repository string
+	chart      string
+
+	runtime *Tracker
+}
+
+func newHelmChartProvider(spec HelmChart, r *Tracker) *helmChartProvider {
+	return &helmChartProvider{
+		repository: spec.Repository,
+		chart:      spec.Chart,
+		runtime:    r,
+	}
+}
+
+var _ ReleaseProvider = &helmChartProvider{}
+
+// helmIndex is the part of the `index.yaml` of a chart repository that we need
+type helmIndex struct {
+	Entries map[string][]helmChartVersion `yaml:"entries"`
+}
+
+type helmChartVersion struct {
+	Version     string    `yaml:"version"`
+	AppVersion  string    `yaml:"appVersion"`
+	Description string    `yaml:"description"`
+	URLs        []string  `yaml:"urls"`
+	Digest      string    `yaml:"digest"`
+	Created     time.Time `yaml:"created"`
+}
+
+// All returns the versions of the chart listed in the index.
+// Each release has the `appVersion`, `urls`, `digest`, and `created` of the entry in Release.Meta,
+// where relative URLs are resolved against the repository URL.
+func (p *helmChartProvider) All() ([]*Release, error) {
+	if p.chart == "" {
+		return nil, fmt.Errorf("chart is required for helmChart %s", p.repository)
+	}
+
+	base := strings.TrimSuffix(p.repository, "/") + "/"
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("parsing helm chart repository url %q: %w", p.repository, err)
+	}
+
+	res, err := p.runtime.httpGetter.Get(base + "index.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var index helmIndex
+	if err := yaml.Unmarshal([]byte(res.Body), &index); err != nil {
+		return nil, fmt.Errorf("parsing index.yaml of %s: %w", p.repository, err)
+	}
+
+	entries, ok := index.Entries[p.chart]
+	if !ok {
+		return nil, fmt.Errorf("chart %q not found in %s", p.chart, base+"index.yaml")
+	}
+
+	var releases []*Release
+
+	for _, e := range entries {
+		r, err := p.runtime.newRelease(e.Version)
+		if err != nil {
+			p.runtime.Logger.V(1).Info("ignoring error", "err", fmt.Errorf("parsing version of chart %s: %q: %v", p.chart, e.Version, err))
+			continue
+		}
+
+		urls := []string{}
+		for _, u := range e.URLs {
+			ref, err := url.Parse(u)
+			if err != nil {
+				return nil, fmt.Errorf("parsing url of chart %s %s: %w", p.chart, e.Version, err)
+			}
+			urls = append(urls, baseURL.ResolveReference(ref).String())
+		}
+
+		r.Description = e.Description
+		r.PublishedAt = e.Created
+		r.Meta = map[string]interface{}{
+			"appVersion": e.AppVersion,
+			"urls":       urls,
+			"digest":     e.Digest,
+		}
+		if !e.Created.IsZero() {
+			r.Meta["created"] = e.Created.UTC().Format(time.RFC3339)
+		}
+
+		releases = append(releases, r)
+	}
+
+	sortReleases(releases, p.runtime.Spec.VersionsFrom.Versioning)
+
+	return releases, nil
+}
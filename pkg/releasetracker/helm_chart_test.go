@@ -0,0 +1,107 @@
+package releasetracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/variantdev/mod/pkg/vhttpget"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProvider_HelmChart(t *testing.T) {
+	input := `releaseChannel:
+  versionsFrom:
+    helmChart:
+      repository: https://charts.example.com/stable/
+      chart: myapp
+`
+
+	conf := &Config{}
+	if err := yaml.Unmarshal([]byte(input), conf); err != nil {
+		t.Fatal(err)
+	}
+
+	index := `apiVersion: v1
+entries:
+  myapp:
+  - name: myapp
+    version: 1.3.0-rc.1
+    appVersion: v2.1.0-rc.1
+    urls:
+    - myapp-1.3.0-rc.1.tgz
+    created: "2020-03-01T00:00:00Z"
+  - name: myapp
+    version: 1.2.0
+    appVersion: v2.0.0
+    description: My app
+    digest: 0123456789abcdef
+    urls:
+    - charts/myapp-1.2.0.tgz
+    - https://mirror.example.com/myapp-1.2.0.tgz
+    created: "2020-02-01T00:00:00.123456789Z"
+  - name: myapp
+    version: 1.1.0
+    appVersion: v1.9.0
+    urls:
+    - myapp-1.1.0.tgz
+    created: "2020-01-01T00:00:00Z"
+  other:
+  - name: other
+    version: 9.9.9
+generated: "2020-03-01T00:00:00Z"
+`
+
+	httpGetter := vhttpget.NewTester(map[string]string{
+		"https://charts.example.com/stable/index.yaml": index,
+	})
+
+	tracker, err := New(conf.ReleaseChannel, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("< 1.3.0-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.2.0" || latest.Description != "My app" {
+		t.Errorf("unexpected release: %+v", latest)
+	}
+
+	if !latest.PublishedAt.Equal(time.Date(2020, 2, 1, 0, 0, 0, 123456789, time.UTC)) {
+		t.Errorf("unexpected publication time: %v", latest.PublishedAt)
+	}
+
+	expectedMeta := map[string]interface{}{
+		"appVersion": "v2.0.0",
+		"digest":     "0123456789abcdef",
+		"urls": []string{
+			"https://charts.example.com/stable/charts/myapp-1.2.0.tgz",
+			"https://mirror.example.com/myapp-1.2.0.tgz",
+		},
+		"created": "2020-02-01T00:00:00Z",
+	}
+	if d := cmp.Diff(expectedMeta, latest.Meta); d != "" {
+		t.Errorf("unexpected meta: %s", d)
+	}
+
+	all, err := tracker.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) != 3 {
+		t.Errorf("expected 3 releases of myapp, got %d", len(all))
+	}
+
+	missing, err := New(Spec{VersionsFrom: VersionsFrom{HelmChart: HelmChart{Repository: "https://charts.example.com/stable", Chart: "missing"}}}, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := missing.GetReleases(); err == nil {
+		t.Error("expected error for a chart missing in the index")
+	}
+}
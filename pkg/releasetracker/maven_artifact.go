@@ -0,0 +1,159 @@
+package releasetracker
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+// MavenArtifact tracks versions of an artifact in a Maven repository like Maven Central, Nexus, or Artifactory
+type MavenArtifact struct {
+	// Repository is the URL of the repository. Defaults to https://repo.maven.apache.org/maven2
+	Repository string `yaml:"repository"`
+	// GroupID is the group ID of the artifact like `io.opentelemetry.javaagent`
+	GroupID string `yaml:"groupId"`
+	// ArtifactID is the artifact ID like `opentelemetry-javaagent`
+	ArtifactID string `yaml:"artifactId"`
+	// ServerID is the ID of the server in `~/.m2/settings.xml` whose username and password are used for basic auth
+	ServerID string `yaml:"serverId"`
+}
+
+const defaultMavenRepository = "https://repo.maven.apache.org/maven2"
+
+type mavenArtifactProvider struct {
+	repository string
+	groupID    string
+	artifactID string
+	serverID   string
+
+	runtime *Tracker
+}
+
+func newMavenArtifactProvider(spec MavenArtifact, r *Tracker) *mavenArtifactProvider {
+	repository := spec.Repository
+	if repository == "" {
+		repository = defaultMavenRepository
+	}
+
+	return &mavenArtifactProvider{
+		repository: repository,
+		groupID:    spec.GroupID,
+		artifactID: spec.ArtifactID,
+		serverID:   spec.ServerID,
+		runtime:    r,
+	}
+}
+
+var _ ReleaseProvider = &mavenArtifactProvider{}
+
+// mavenMetadata is the `maven-metadata.xml` at the artifact level, which lists all the versions of the artifact
+type mavenMetadata struct {
+	Versioning struct {
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+// All returns the versions listed in `<repository>/<groupId as path>/<artifactId>/maven-metadata.xml`,
+// excluding snapshots like `1.0.0-SNAPSHOT`
+func (p *mavenArtifactProvider) All() ([]*Release, error) {
+	if p.groupID == "" || p.artifactID == "" {
+		return nil, fmt.Errorf("groupId and artifactId are required for mavenArtifact")
+	}
+
+	header := map[string]string{}
+
+	if p.serverID != "" {
+		username, password, err := p.runtime.mavenServerCredentials(p.serverID)
+		if err != nil {
+			return nil, err
+		}
+
+		header["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/maven-metadata.xml", strings.TrimSuffix(p.repository, "/"), strings.ReplaceAll(p.groupID, ".", "/"), p.artifactID)
+
+	res, err := p.runtime.httpGetter.Get(url, vhttpget.Opts{Header: header})
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata mavenMetadata
+	if err := xml.Unmarshal([]byte(res.Body), &metadata); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", url, err)
+	}
+
+	var versions []string
+	for _, v := range metadata.Versioning.Versions {
+		v = strings.TrimSpace(v)
+
+		if strings.HasSuffix(strings.ToUpper(v), "-SNAPSHOT") {
+			continue
+		}
+
+		versions = append(versions, v)
+	}
+
+	return p.runtime.versionsToReleases(versions)
+}
+
+// mavenSettings is the part of `~/.m2/settings.xml` for authenticating with repositories
+type mavenSettings struct {
+	Servers []struct {
+		ID       string `xml:"id"`
+		Username string `xml:"username"`
+		Password string `xml:"password"`
+	} `xml:"servers>server"`
+}
+
+var mavenSettingsEnvRef = regexp.MustCompile(`\$\{env\.([^}]+)\}`)
+
+// mavenServerCredentials returns the username and password of the server in `~/.m2/settings.xml`,
+// where references to environment variables like `${env.NEXUS_PASSWORD}` are expanded as Maven does
+func (p *Tracker) mavenServerCredentials(serverID string) (string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	file := filepath.Join(home, ".m2", "settings.xml")
+
+	data, err := p.fs.ReadFile(file)
+	if err != nil {
+		return "", "", fmt.Errorf("reading credentials of maven server %s: %w", serverID, err)
+	}
+
+	var settings mavenSettings
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return "", "", fmt.Errorf("decoding %s: %w", file, err)
+	}
+
+	expand := func(s string) string {
+		return mavenSettingsEnvRef.ReplaceAllStringFunc(strings.TrimSpace(s), func(ref string) string {
+			return os.Getenv(mavenSettingsEnvRef.FindStringSubmatch(ref)[1])
+		})
+	}
+
+	for _, s := range settings.Servers {
+		if strings.TrimSpace(s.ID) != serverID {
+			continue
+		}
+
+		password := expand(s.Password)
+
+		// Passwords encrypted with `mvn --encrypt-password` are enclosed in braces, which needs the master password to decrypt
+		if strings.HasPrefix(password, "{") && strings.HasSuffix(password, "}") {
+			return "", "", fmt.Errorf("encrypted password of maven server %s in %s is not supported: use a plain password or ${env.NAME} instead", serverID, file)
+		}
+
+		return expand(s.Username), password, nil
+	}
+
+	return "", "", fmt.Errorf("maven server %s not found in %s", serverID, file)
+}
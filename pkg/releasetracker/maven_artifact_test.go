@@ -0,0 +1,158 @@
+package releasetracker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_MavenArtifact(t *testing.T) {
+	fs, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/home/user/.m2/settings.xml": `<settings>
+  <servers>
+    <server>
+      <id>other</id>
+      <username>other</username>
+      <password>other</password>
+    </server>
+    <server>
+      <id>nexus</id>
+      <username>deployer</username>
+      <password>${env.NEXUS_PASSWORD}</password>
+    </server>
+    <server>
+      <id>encrypted</id>
+      <username>deployer</username>
+      <password>{COQLCE6DU6GtcS5P=}</password>
+    </server>
+  </servers>
+</settings>`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	t.Setenv("HOME", "/home/user")
+	t.Setenv("NEXUS_PASSWORD", "secret")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repository/maven-releases/io/opentelemetry/javaagent/opentelemetry-javaagent/maven-metadata.xml" {
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if username, password, ok := r.BasicAuth(); !ok || username != "deployer" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>io.opentelemetry.javaagent</groupId>
+  <artifactId>opentelemetry-javaagent</artifactId>
+  <versioning>
+    <latest>2.1.0-SNAPSHOT</latest>
+    <release>2.0.0</release>
+    <versions>
+      <version>1.32.0</version>
+      <version>1.9.0</version>
+      <version>2.0.0-RC1</version>
+      <version>2.0.0</version>
+      <version>2.1.0-SNAPSHOT</version>
+    </versions>
+    <lastUpdated>20240115120000</lastUpdated>
+  </versioning>
+</metadata>`))
+	}))
+	defer server.Close()
+
+	spec := MavenArtifact{
+		Repository: server.URL + "/repository/maven-releases/",
+		GroupID:    "io.opentelemetry.javaagent",
+		ArtifactID: "opentelemetry-javaagent",
+		ServerID:   "nexus",
+	}
+
+	tracker, err := New(Spec{VersionsFrom: VersionsFrom{MavenArtifact: spec}}, FS(fs), HttpGetter(vhttpget.NewWithClient(server.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := tracker.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, r := range releases {
+		actual = append(actual, r.Version)
+	}
+
+	if d := cmp.Diff([]string{"1.9.0", "1.32.0", "2.0.0-RC1", "2.0.0"}, actual); d != "" {
+		t.Errorf("unexpected versions: %s", d)
+	}
+
+	latest, err := tracker.Latest("< 2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.32.0" {
+		t.Errorf("unexpected latest version: %s", latest.Version)
+	}
+
+	for _, serverID := range []string{"", "encrypted", "missing"} {
+		spec.ServerID = serverID
+
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{MavenArtifact: spec}}, FS(fs), HttpGetter(vhttpget.NewWithClient(server.Client())))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tracker.GetReleases(); err == nil {
+			t.Errorf("expected error for server %q", serverID)
+		}
+	}
+}
+
+func TestProvider_MavenArtifact_Central(t *testing.T) {
+	tracker, err := New(
+		Spec{VersionsFrom: VersionsFrom{MavenArtifact: MavenArtifact{GroupID: "org.slf4j", ArtifactID: "slf4j-api"}}},
+		HttpGetter(vhttpget.NewTester(map[string]string{
+			"https://repo.maven.apache.org/maven2/org/slf4j/slf4j-api/maven-metadata.xml": `<metadata>
+  <versioning>
+    <versions>
+      <version>2.0.0-alpha1</version>
+      <version>1.7.36</version>
+      <version>2.0.0</version>
+      <version>2.0.0-beta1</version>
+    </versions>
+  </versioning>
+</metadata>`,
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := tracker.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, r := range releases {
+		actual = append(actual, r.Version)
+	}
+
+	if d := cmp.Diff([]string{"1.7.36", "2.0.0-alpha1", "2.0.0-beta1", "2.0.0"}, actual); d != "" {
+		t.Errorf("unexpected versions: %s", d)
+	}
+}
@@ -0,0 +1,241 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+// NpmPackage tracks versions of a package in an npm registry
+type NpmPackage struct {
+	// Name is the package name like `typescript` or `@types/node`
+	Name string `yaml:"name"`
+
+	// Registry is the URL of the registry. Defaults to the one configured in .npmrc for the scope of the package,
+	// or https://registry.npmjs.org
+	Registry string `yaml:"registry"`
+
+	// DistTag is the dist-tag like `latest` or `next`. Versions newer than the one the dist-tag points to are skipped
+	DistTag string `yaml:"distTag"`
+}
+
+const defaultNpmRegistry = "https://registry.npmjs.org"
+
+type npmPackageProvider struct {
+	name     string
+	registry string
+	distTag  string
+
+	runtime *Tracker
+}
+
+func newNpmPackageProvider(spec NpmPackage, r *Tracker) *npmPackageProvider {
+	return &npmPackageProvider{
+		name:     spec.Name,
+		registry: spec.Registry,
+		distTag:  spec.DistTag,
+		runtime:  r,
+	}
+}
+
+var _ ReleaseProvider = &npmPackageProvider{}
+
+// npmPackument is the part of the package document returned by the registry that we need
+type npmPackument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]struct {
+		// Deprecated is the deprecation message, or false for versions that are not deprecated
+		Deprecated interface{} `json:"deprecated"`
+	} `json:"versions"`
+	Time map[string]time.Time `json:"time"`
+}
+
+// All returns the versions of the package excluding deprecated ones.
+// Each release has the publication time as `time` and the names of the dist-tags pointing to it as `distTags` in Release.Meta.
+func (p *npmPackageProvider) All() ([]*Release, error) {
+	if p.name == "" {
+		return nil, fmt.Errorf("name is required for npmPackage")
+	}
+
+	npmrc, err := p.runtime.loadNpmrc()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := p.registry
+	if registry == "" {
+		registry = npmrc.registryFor(p.name)
+	}
+	registry = strings.TrimSuffix(registry, "/")
+
+	// Scoped packages are requested as `@scope%2fname`, as the npm CLI does
+	u := registry + "/" + strings.Replace(p.name, "/", "%2f", 1)
+
+	header := map[string]string{
+		"Accept": "application/json",
+	}
+	if auth := npmrc.authorizationFor(registry); auth != "" {
+		header["Authorization"] = auth
+	}
+
+	res, err := p.runtime.httpGetter.Get(u, vhttpget.Opts{Header: header})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc npmPackument
+	if err := json.Unmarshal([]byte(res.Body), &doc); err != nil {
+		return nil, fmt.Errorf("decoding packument of %s: %w", p.name, err)
+	}
+
+	tagsOf := map[string][]string{}
+	for tag, v := range doc.DistTags {
+		tagsOf[v] = append(tagsOf[v], tag)
+	}
+
+	var ceiling *Release
+	if p.distTag != "" {
+		v, ok := doc.DistTags[p.distTag]
+		if !ok {
+			return nil, fmt.Errorf("dist-tag %q not found for %s", p.distTag, p.name)
+		}
+		ceiling, err = p.runtime.newRelease(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing version %q of dist-tag %q: %w", v, p.distTag, err)
+		}
+	}
+
+	var releases []*Release
+
+	for v, meta := range doc.Versions {
+		if d, ok := meta.Deprecated.(string); ok && d != "" {
+			p.runtime.Logger.V(1).Info("ignoring deprecated version", "package", p.name, "version", v, "deprecated", d)
+			continue
+		}
+
+		r, err := p.runtime.newRelease(v)
+		if err != nil {
+			p.runtime.Logger.V(1).Info("ignoring error", "err", fmt.Errorf("parsing version of package %s: %q: %v", p.name, v, err))
+			continue
+		}
+
+		if ceiling != nil && compareReleases(p.runtime.versioning(), r, ceiling) > 0 {
+			continue
+		}
+
+		r.Meta = map[string]interface{}{}
+
+		if t, ok := doc.Time[v]; ok {
+			r.PublishedAt = t
+			r.Meta["time"] = t.UTC().Format(time.RFC3339)
+		}
+
+		if tags := tagsOf[v]; len(tags) > 0 {
+			sort.Strings(tags)
+			r.Meta["distTags"] = tags
+		}
+
+		releases = append(releases, r)
+	}
+
+	sortReleases(releases, p.runtime.Spec.VersionsFrom.Versioning)
+
+	return releases, nil
+}
+
+// npmrc is the configuration read from .npmrc files
+type npmrc map[string]string
+
+// loadNpmrc reads the user .npmrc, and the project .npmrc in the working directory which takes precedence.
+// `${VAR}` in values are expanded with environment variables, as npm does.
+func (p *Tracker) loadNpmrc() (npmrc, error) {
+	var files []string
+
+	if f := os.Getenv("NPM_CONFIG_USERCONFIG"); f != "" {
+		files = append(files, f)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".npmrc"))
+	}
+
+	files = append(files, filepath.Join(p.AbsWorkDir, ".npmrc"))
+
+	conf := npmrc{}
+
+	for _, f := range files {
+		data, err := p.fs.ReadFile(f)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				continue
+			}
+
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+
+			conf[strings.TrimSpace(kv[0])] = os.ExpandEnv(strings.TrimSpace(kv[1]))
+		}
+	}
+
+	return conf, nil
+}
+
+// registryFor returns the registry for the package, configured by `@scope:registry` or `registry`
+func (c npmrc) registryFor(name string) string {
+	if strings.HasPrefix(name, "@") {
+		if i := strings.Index(name, "/"); i > 0 {
+			if r, ok := c[name[:i]+":registry"]; ok {
+				return r
+			}
+		}
+	}
+
+	if r, ok := c["registry"]; ok {
+		return r
+	}
+
+	return defaultNpmRegistry
+}
+
+// authorizationFor returns the Authorization header for the registry, from the `_authToken` or `_auth` configured for
+// the longest matching `//host/path/` prefix of the registry URL
+func (c npmrc) authorizationFor(registry string) string {
+	u, err := url.Parse(registry)
+	if err != nil {
+		return ""
+	}
+
+	prefix := "//" + u.Host + strings.TrimSuffix(u.Path, "/") + "/"
+
+	for {
+		if token, ok := c[prefix+":_authToken"]; ok {
+			return "Bearer " + token
+		}
+
+		if auth, ok := c[prefix+":_auth"]; ok {
+			return "Basic " + auth
+		}
+
+		i := strings.LastIndex(strings.TrimSuffix(prefix, "/"), "/")
+		if i < 2 {
+			break
+		}
+		prefix = prefix[:i+1]
+	}
+
+	return ""
+}
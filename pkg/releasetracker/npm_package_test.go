@@ -0,0 +1,179 @@
+package releasetracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_NpmPackage(t *testing.T) {
+	packument := `{
+  "name": "@myorg/mylib",
+  "dist-tags": {"latest": "1.2.0", "beta": "1.3.0-beta.1", "stable": "1.2.0"},
+  "versions": {
+    "1.0.0": {"version": "1.0.0"},
+    "1.1.0": {"version": "1.1.0", "deprecated": "critical bug, use 1.2.0"},
+    "1.2.0": {"version": "1.2.0", "deprecated": false},
+    "1.3.0-beta.1": {"version": "1.3.0-beta.1"}
+  },
+  "time": {
+    "created": "2020-01-01T00:00:00.000Z",
+    "modified": "2020-04-01T00:00:00.000Z",
+    "1.0.0": "2020-01-01T00:00:00.000Z",
+    "1.1.0": "2020-02-01T00:00:00.000Z",
+    "1.2.0": "2020-03-01T00:00:00.000Z",
+    "1.3.0-beta.1": "2020-04-01T00:00:00.000Z"
+  }
+}`
+
+	httpGetter := vhttpget.NewTester(map[string]string{
+		"https://npm.example.com/@myorg%2fmylib":    packument,
+		"https://registry.npmjs.org/@myorg%2fmylib": packument,
+	})
+
+	fs, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/home/user/.npmrc":       "@myorg:registry=https://npm.example.com/\n",
+		"/home/nothing/.npmrc":    "; nothing configured\n",
+		"/path/to/project/.npmrc": "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	testcases := []struct {
+		spec       NpmPackage
+		userconfig string
+		constraint string
+		expected   string
+		meta       map[string]interface{}
+		count      int
+	}{
+		{
+			spec:       NpmPackage{Name: "@myorg/mylib"},
+			userconfig: "/home/user/.npmrc",
+			constraint: ">= 1.0.0-0",
+			expected:   "1.3.0-beta.1",
+			meta:       map[string]interface{}{"time": "2020-04-01T00:00:00Z", "distTags": []string{"beta"}},
+			count:      3,
+		},
+		{
+			spec:       NpmPackage{Name: "@myorg/mylib", DistTag: "latest"},
+			userconfig: "/home/nothing/.npmrc",
+			constraint: ">= 1.0.0-0",
+			expected:   "1.2.0",
+			meta:       map[string]interface{}{"time": "2020-03-01T00:00:00Z", "distTags": []string{"latest", "stable"}},
+			count:      2,
+		},
+		{
+			spec:       NpmPackage{Name: "@myorg/mylib", Registry: "https://npm.example.com"},
+			userconfig: "/home/nothing/.npmrc",
+			constraint: "< 1.2.0",
+			expected:   "1.0.0",
+			meta:       map[string]interface{}{"time": "2020-01-01T00:00:00Z"},
+			count:      3,
+		},
+	}
+
+	for i, tc := range testcases {
+		t.Setenv("NPM_CONFIG_USERCONFIG", tc.userconfig)
+
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{NpmPackage: tc.spec}}, HttpGetter(httpGetter), FS(fs), WD("/path/to/project"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest(tc.constraint)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if latest.Version != tc.expected {
+			t.Errorf("#%d: unexpected version: expected=%s, got=%s", i, tc.expected, latest.Version)
+		}
+
+		if d := cmp.Diff(tc.meta, latest.Meta); d != "" {
+			t.Errorf("#%d: unexpected meta: %s", i, d)
+		}
+
+		if published, _ := time.Parse(time.RFC3339, tc.meta["time"].(string)); !latest.PublishedAt.Equal(published) {
+			t.Errorf("#%d: unexpected publication time: %v", i, latest.PublishedAt)
+		}
+
+		all, err := tracker.GetReleases()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(all) != tc.count {
+			t.Errorf("#%d: expected %d releases, got %d", i, tc.count, len(all))
+		}
+	}
+
+	tracker, err := New(Spec{VersionsFrom: VersionsFrom{NpmPackage: NpmPackage{Name: "@myorg/mylib", DistTag: "next"}}}, HttpGetter(httpGetter), FS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tracker.GetReleases(); err == nil {
+		t.Error("expected error for a missing dist-tag")
+	}
+}
+
+func TestNpmrc(t *testing.T) {
+	t.Setenv("NPM_TOKEN", "secret")
+
+	fs, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/home/user/.npmrc": `registry=https://npm.example.com/
+//npm.example.com/:_authToken=user-token
+//npm.pkg.github.com/:_authToken=${NPM_TOKEN}
+`,
+		"/path/to/project/.npmrc": `@myorg:registry=https://npm.pkg.github.com
+//npm.example.com/:_authToken=project-token
+//artifacts.example.com/api/npm/:_auth=dXNlcjpwYXNz
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	t.Setenv("NPM_CONFIG_USERCONFIG", "/home/user/.npmrc")
+
+	tracker, err := New(Spec{}, FS(fs), WD("/path/to/project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := tracker.loadNpmrc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registries := map[string]string{
+		"@myorg/mylib":   "https://npm.pkg.github.com",
+		"@other/lib":     "https://npm.example.com/",
+		"left-pad":       "https://npm.example.com/",
+		"@myorg-foo/lib": "https://npm.example.com/",
+	}
+	for name, expected := range registries {
+		if got := conf.registryFor(name); got != expected {
+			t.Errorf("unexpected registry for %s: expected=%s, got=%s", name, expected, got)
+		}
+	}
+
+	auths := map[string]string{
+		"https://npm.example.com":                          "Bearer project-token",
+		"https://npm.pkg.github.com":                       "Bearer secret",
+		"https://artifacts.example.com/api/npm/npm-remote": "Basic dXNlcjpwYXNz",
+		"https://registry.npmjs.org":                       "",
+	}
+	for registry, expected := range auths {
+		if got := conf.authorizationFor(registry); got != expected {
+			t.Errorf("unexpected authorization for %s: expected=%q, got=%q", registry, expected, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/variantdev/mod/pkg/versioning"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+// PyPIPackage tracks versions of a Python package in a package index that serves the JSON simple API in PEP 691
+type PyPIPackage struct {
+	// Name is the project name like `awscli`
+	Name string `yaml:"name"`
+
+	// Index is the URL of the simple API of the index. Defaults to https://pypi.org/simple
+	Index string `yaml:"index"`
+}
+
+const defaultPyPIIndex = "https://pypi.org/simple"
+
+type pypiPackageProvider struct {
+	name  string
+	index string
+
+	runtime *Tracker
+}
+
+func newPyPIPackageProvider(spec PyPIPackage, r *Tracker) *pypiPackageProvider {
+	index := spec.Index
+	if index == "" {
+		index = defaultPyPIIndex
+	}
+
+	return &pypiPackageProvider{
+		name:    spec.Name,
+		index:   index,
+		runtime: r,
+	}
+}
+
+var _ ReleaseProvider = &pypiPackageProvider{}
+
+// pypiProject is the project detail returned by the JSON simple API
+type pypiProject struct {
+	Files []pypiFile `json:"files"`
+}
+
+type pypiFile struct {
+	Filename       string `json:"filename"`
+	RequiresPython string `json:"requires-python"`
+	// Yanked is true or the reason for the yank for yanked files, or false
+	Yanked     interface{} `json:"yanked"`
+	UploadTime *time.Time  `json:"upload-time"`
+}
+
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePyPIName normalizes the project name as specified in PEP 503
+func normalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// All returns the versions of the package that have one or more files that aren't yanked.
+// Versions are normalized as specified in PEP 440, like `2.0.0rc1`.
+// Each release has the `requires-python` of the files as `requiresPython`,
+// and the time the first file was uploaded as `uploadTime` in Release.Meta.
+func (p *pypiPackageProvider) All() ([]*Release, error) {
+	if p.name == "" {
+		return nil, fmt.Errorf("name is required for pypiPackage")
+	}
+
+	name := normalizePyPIName(p.name)
+
+	res, err := p.runtime.httpGetter.Get(strings.TrimSuffix(p.index, "/")+"/"+name+"/", vhttpget.Opts{
+		Header: map[string]string{
+			"Accept": "application/vnd.pypi.simple.v1+json",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var project pypiProject
+	if err := json.Unmarshal([]byte(res.Body), &project); err != nil {
+		return nil, fmt.Errorf("decoding project %s from %s, which needs to support the JSON simple API in PEP 691: %w", p.name, p.index, err)
+	}
+
+	releases := map[string]*Release{}
+
+	var order []string
+
+	for _, f := range project.Files {
+		if y, ok := f.Yanked.(bool); ok && y {
+			continue
+		} else if y, ok := f.Yanked.(string); ok {
+			p.runtime.Logger.V(1).Info("ignoring yanked file", "file", f.Filename, "reason", y)
+			continue
+		}
+
+		v, ok := pypiFileVersion(name, f.Filename)
+		if !ok {
+			continue
+		}
+
+		v, err := versioning.NormalizePEP440(v)
+		if err != nil {
+			p.runtime.Logger.V(1).Info("ignoring error", "err", fmt.Errorf("parsing version of file %s: %v", f.Filename, err))
+			continue
+		}
+
+		r, ok := releases[v]
+		if !ok {
+			r, err = p.runtime.newRelease(v)
+			if err != nil {
+				p.runtime.Logger.V(1).Info("ignoring error", "err", fmt.Errorf("parsing version of package %s: %q: %v", p.name, v, err))
+				continue
+			}
+			r.Meta = map[string]interface{}{}
+			releases[v] = r
+			order = append(order, v)
+		}
+
+		if _, ok := r.Meta["requiresPython"]; !ok && f.RequiresPython != "" {
+			r.Meta["requiresPython"] = f.RequiresPython
+		}
+
+		if t := f.UploadTime; t != nil && (r.PublishedAt.IsZero() || t.Before(r.PublishedAt)) {
+			r.PublishedAt = *t
+			r.Meta["uploadTime"] = t.UTC().Format(time.RFC3339)
+		}
+	}
+
+	var rs []*Release
+	for _, v := range order {
+		rs = append(rs, releases[v])
+	}
+
+	sortReleases(rs, p.runtime.Spec.VersionsFrom.Versioning)
+
+	return rs, nil
+}
+
+var pypiSdistExtensions = []string{".tar.gz", ".zip", ".tar.bz2", ".tar.xz", ".tgz", ".tar"}
+
+// pypiFileVersion extracts the version out of the filename of a wheel, an egg, or a source distribution.
+// The name is the normalized project name, which is used to find the version in source distributions
+// whose names contain dashes, like `python-dateutil-2.8.2.tar.gz`.
+func pypiFileVersion(name, filename string) (string, bool) {
+	if strings.HasSuffix(filename, ".whl") || strings.HasSuffix(filename, ".egg") {
+		parts := strings.Split(filename, "-")
+		if len(parts) < 3 {
+			return "", false
+		}
+		return parts[1], true
+	}
+
+	for _, ext := range pypiSdistExtensions {
+		if !strings.HasSuffix(filename, ext) {
+			continue
+		}
+
+		base := strings.TrimSuffix(filename, ext)
+
+		for i := strings.Index(base, "-"); i > 0; i = nextIndex(base, "-", i) {
+			if v := base[i+1:]; normalizePyPIName(base[:i]) == name && v != "" && unicode.IsDigit(rune(v[0])) {
+				return v, true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+// nextIndex returns the index of the next occurrence of sep in s after i, or -1
+func nextIndex(s, sep string, i int) int {
+	j := strings.Index(s[i+1:], sep)
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
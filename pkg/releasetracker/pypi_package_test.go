@@ -0,0 +1,133 @@
+package releasetracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_PyPIPackage(t *testing.T) {
+	project := `{
+  "meta": {"api-version": "1.1"},
+  "name": "python-dateutil",
+  "files": [
+    {"filename": "python-dateutil-2.8.1.tar.gz", "requires-python": ">=2.7", "yanked": false, "upload-time": "2019-11-03T05:42:00.000000Z"},
+    {"filename": "python_dateutil-2.8.1-py2.py3-none-any.whl", "requires-python": ">=2.7", "yanked": false, "upload-time": "2019-11-03T05:41:00.000000Z"},
+    {"filename": "python-dateutil-2.8.2.tar.gz", "requires-python": ">=2.7, !=3.0.*", "yanked": false, "upload-time": "2021-07-08T19:40:00.000000Z"},
+    {"filename": "python_dateutil-2.8.2-py2.py3-none-any.whl", "requires-python": ">=2.7, !=3.0.*", "yanked": false, "upload-time": "2021-07-08T19:39:00.000000Z"},
+    {"filename": "python_dateutil-2.9.0-py2.py3-none-any.whl", "requires-python": ">=3.8", "yanked": "broken metadata", "upload-time": "2024-02-29T00:00:00.000000Z"},
+    {"filename": "python-dateutil-2.9.0.post0.tar.gz", "requires-python": ">=3.8", "yanked": false, "upload-time": "2024-03-01T00:00:00.000000Z"},
+    {"filename": "python_dateutil-3.0.0RC1-py3-none-any.whl", "requires-python": ">=3.8", "yanked": false, "upload-time": "2024-04-01T00:00:00.000000Z"},
+    {"filename": "python_dateutil-3.0.0b1-py3-none-any.whl", "requires-python": ">=3.8", "yanked": true, "upload-time": "2024-03-15T00:00:00.000000Z"},
+    {"filename": "python-dateutil-2.8.2.win32.exe", "yanked": false}
+  ]
+}`
+
+	httpGetter := vhttpget.NewTester(map[string]string{
+		"https://pypi.org/simple/python-dateutil/":              project,
+		"https://pypi.example.com/simple/python-dateutil/":      project,
+		"https://pypi.example.com/simple/python-dateutil-html/": `<!DOCTYPE html><html></html>`,
+	})
+
+	testcases := []struct {
+		spec       PyPIPackage
+		constraint string
+		expected   string
+		meta       map[string]interface{}
+	}{
+		{
+			spec:       PyPIPackage{Name: "Python_Dateutil"},
+			constraint: ">= 2",
+			expected:   "2.9.0.post0",
+			meta:       map[string]interface{}{"requiresPython": ">=3.8", "uploadTime": "2024-03-01T00:00:00Z"},
+		},
+		{
+			spec:       PyPIPackage{Name: "python-dateutil", Index: "https://pypi.example.com/simple/"},
+			constraint: "< 2.9",
+			expected:   "2.8.2",
+			meta:       map[string]interface{}{"requiresPython": ">=2.7, !=3.0.*", "uploadTime": "2021-07-08T19:39:00Z"},
+		},
+		{
+			spec:       PyPIPackage{Name: "python-dateutil"},
+			constraint: ">= 3.0.0-0",
+			expected:   "3.0.0rc1",
+			meta:       map[string]interface{}{"requiresPython": ">=3.8", "uploadTime": "2024-04-01T00:00:00Z"},
+		},
+	}
+
+	for i, tc := range testcases {
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{PyPIPackage: tc.spec}}, HttpGetter(httpGetter))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest(tc.constraint)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if latest.Version != tc.expected {
+			t.Errorf("#%d: unexpected version: expected=%s, got=%s", i, tc.expected, latest.Version)
+		}
+
+		if d := cmp.Diff(tc.meta, latest.Meta); d != "" {
+			t.Errorf("#%d: unexpected meta: %s", i, d)
+		}
+
+		if published, _ := time.Parse(time.RFC3339, tc.meta["uploadTime"].(string)); !latest.PublishedAt.Equal(published) {
+			t.Errorf("#%d: unexpected publication time: %v", i, latest.PublishedAt)
+		}
+	}
+
+	tracker, err := New(Spec{VersionsFrom: VersionsFrom{PyPIPackage: PyPIPackage{Name: "python-dateutil"}}}, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := tracker.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []string
+	for _, r := range all {
+		versions = append(versions, r.Version)
+	}
+
+	if d := cmp.Diff([]string{"2.8.1", "2.8.2", "2.9.0.post0", "3.0.0rc1"}, versions); d != "" {
+		t.Errorf("unexpected versions: %s", d)
+	}
+
+	html, err := New(Spec{VersionsFrom: VersionsFrom{PyPIPackage: PyPIPackage{Name: "python-dateutil-html", Index: "https://pypi.example.com/simple"}}}, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := html.GetReleases(); err == nil {
+		t.Error("expected error for an index not supporting the JSON simple API")
+	}
+}
+
+func TestPyPIFileVersion(t *testing.T) {
+	testcases := []struct {
+		name, filename, expected string
+	}{
+		{name: "awscli", filename: "awscli-1.32.0.tar.gz", expected: "1.32.0"},
+		{name: "awscli", filename: "awscli-1.32.0-py3-none-any.whl", expected: "1.32.0"},
+		{name: "python-dateutil", filename: "python-dateutil-2.8.2.tar.gz", expected: "2.8.2"},
+		{name: "python-dateutil", filename: "python_dateutil-2.8.2-1-py3-none-any.whl", expected: "2.8.2"},
+		{name: "python-dateutil", filename: "python-dateutil-2.8.2.win32.exe"},
+		{name: "zope-interface", filename: "zope.interface-6.0.zip", expected: "6.0"},
+		{name: "setuptools", filename: "setuptools-0.6c11-py2.7.egg", expected: "0.6c11"},
+		{name: "other", filename: "other-project-1.0.tar.gz"},
+	}
+
+	for _, tc := range testcases {
+		actual, ok := pypiFileVersion(tc.name, tc.filename)
+		if actual != tc.expected || ok != (tc.expected != "") {
+			t.Errorf("unexpected version of %s: expected=%q, got=%q", tc.filename, tc.expected, actual)
+		}
+	}
+}
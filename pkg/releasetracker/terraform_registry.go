@@ -0,0 +1,224 @@
+package releasetracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+// TerraformProvider tracks versions of a provider in a Terraform registry
+type TerraformProvider struct {
+	// Host is the hostname of the registry. Defaults to registry.terraform.io
+	Host string `yaml:"host"`
+	// Namespace is the namespace of the provider like `hashicorp`
+	Namespace string `yaml:"namespace"`
+	// Type is the type of the provider like `aws`
+	Type string `yaml:"type"`
+}
+
+// TerraformModule tracks versions of a module in a Terraform registry
+type TerraformModule struct {
+	// Host is the hostname of the registry. Defaults to registry.terraform.io
+	Host string `yaml:"host"`
+	// Namespace is the namespace of the module like `terraform-aws-modules`
+	Namespace string `yaml:"namespace"`
+	// Name is the name of the module like `vpc`
+	Name string `yaml:"name"`
+	// System is the name of the remote system the module targets like `aws`
+	System string `yaml:"system"`
+}
+
+const defaultTerraformRegistryHost = "registry.terraform.io"
+
+type terraformProviderProvider struct {
+	host      string
+	namespace string
+	typ       string
+
+	runtime *Tracker
+}
+
+func newTerraformProviderProvider(spec TerraformProvider, r *Tracker) *terraformProviderProvider {
+	return &terraformProviderProvider{
+		host:      spec.Host,
+		namespace: spec.Namespace,
+		typ:       spec.Type,
+		runtime:   r,
+	}
+}
+
+var _ ReleaseProvider = &terraformProviderProvider{}
+
+// All returns the versions in `<providers.v1>/<namespace>/<type>/versions` of the registry
+func (p *terraformProviderProvider) All() ([]*Release, error) {
+	if p.namespace == "" || p.typ == "" {
+		return nil, fmt.Errorf("namespace and type are required for terraformProvider")
+	}
+
+	var res struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	}
+
+	if err := p.runtime.getTerraformRegistry(p.host, "providers.v1", p.namespace+"/"+p.typ+"/versions", &res); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, v := range res.Versions {
+		versions = append(versions, v.Version)
+	}
+
+	return p.runtime.versionsToReleases(versions)
+}
+
+type terraformModuleProvider struct {
+	host      string
+	namespace string
+	name      string
+	system    string
+
+	runtime *Tracker
+}
+
+func newTerraformModuleProvider(spec TerraformModule, r *Tracker) *terraformModuleProvider {
+	return &terraformModuleProvider{
+		host:      spec.Host,
+		namespace: spec.Namespace,
+		name:      spec.Name,
+		system:    spec.System,
+		runtime:   r,
+	}
+}
+
+var _ ReleaseProvider = &terraformModuleProvider{}
+
+// All returns the versions in `<modules.v1>/<namespace>/<name>/<system>/versions` of the registry
+func (p *terraformModuleProvider) All() ([]*Release, error) {
+	if p.namespace == "" || p.name == "" || p.system == "" {
+		return nil, fmt.Errorf("namespace, name, and system are required for terraformModule")
+	}
+
+	var res struct {
+		Modules []struct {
+			Versions []struct {
+				Version string `json:"version"`
+			} `json:"versions"`
+		} `json:"modules"`
+	}
+
+	if err := p.runtime.getTerraformRegistry(p.host, "modules.v1", p.namespace+"/"+p.name+"/"+p.system+"/versions", &res); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, m := range res.Modules {
+		for _, v := range m.Versions {
+			versions = append(versions, v.Version)
+		}
+	}
+
+	return p.runtime.versionsToReleases(versions)
+}
+
+// getTerraformRegistry discovers the base URL of the service like `providers.v1` with `/.well-known/terraform.json`
+// of the host, and decodes the JSON at the path relative to the base URL into v.
+// The token for the host is sent as the bearer token, as the Terraform CLI does.
+func (p *Tracker) getTerraformRegistry(host, service, path string, v interface{}) error {
+	if host == "" {
+		host = defaultTerraformRegistryHost
+	}
+
+	header := map[string]string{}
+
+	token, err := p.terraformToken(host)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		header["Authorization"] = "Bearer " + token
+	}
+
+	discoveryURL := "https://" + host + "/.well-known/terraform.json"
+
+	res, err := p.httpGetter.Get(discoveryURL, vhttpget.Opts{Header: header})
+	if err != nil {
+		return fmt.Errorf("discovering services of terraform registry %s: %w", host, err)
+	}
+
+	var services map[string]interface{}
+	if err := json.Unmarshal([]byte(res.Body), &services); err != nil {
+		return fmt.Errorf("decoding %s: %w", discoveryURL, err)
+	}
+
+	base, ok := services[service].(string)
+	if !ok {
+		return fmt.Errorf("terraform registry %s does not provide %s", host, service)
+	}
+
+	// The base URL can be relative to the discovery URL, like `/v1/providers/`
+	u, err := url.Parse(discoveryURL)
+	if err != nil {
+		return err
+	}
+
+	baseURL, err := u.Parse(base)
+	if err != nil {
+		return fmt.Errorf("parsing %s of terraform registry %s: %w", service, host, err)
+	}
+
+	apiURL := strings.TrimSuffix(baseURL.String(), "/") + "/" + path
+
+	res, err = p.httpGetter.Get(apiURL, vhttpget.Opts{Header: header})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(res.Body), v); err != nil {
+		return fmt.Errorf("decoding %s: %w", apiURL, err)
+	}
+
+	return nil
+}
+
+// terraformToken returns the API token for the registry host from the `TF_TOKEN_<host>` environment variable,
+// or from the credentials file written by `terraform login`
+func (p *Tracker) terraformToken(host string) (string, error) {
+	// Dots in the hostname are encoded as underscores, and dashes as double underscores, like `TF_TOKEN_app_terraform_io`
+	env := "TF_TOKEN_" + strings.NewReplacer("-", "__", ".", "_").Replace(host)
+	if t := os.Getenv(env); t != "" {
+		return t, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+
+	file := filepath.Join(home, ".terraform.d", "credentials.tfrc.json")
+
+	data, err := p.fs.ReadFile(file)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var creds struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", fmt.Errorf("decoding %s: %w", file, err)
+	}
+
+	return creds.Credentials[host].Token, nil
+}
@@ -0,0 +1,158 @@
+package releasetracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/vhttpget"
+)
+
+func TestProvider_TerraformRegistry(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a := r.Header.Get("Authorization"); a != "Bearer secret" {
+			t.Errorf("unexpected authorization for %s: %q", r.URL.Path, a)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/.well-known/terraform.json":
+			// Services can be relative to the discovery URL or absolute
+			fmt.Fprintf(w, `{"modules.v1": "/api/registry/v1/modules/", "providers.v1": "%s/api/registry/v1/providers"}`, server.URL)
+		case "/api/registry/v1/providers/myorg/mycloud/versions":
+			fmt.Fprint(w, `{"id": "myorg/mycloud", "versions": [
+  {"version": "1.0.0", "protocols": ["5.0"], "platforms": [{"os": "linux", "arch": "amd64"}]},
+  {"version": "1.1.0", "protocols": ["5.0"], "platforms": [{"os": "linux", "arch": "amd64"}]},
+  {"version": "2.0.0-beta1", "protocols": ["6.0"], "platforms": [{"os": "linux", "arch": "amd64"}]}
+]}`)
+		case "/api/registry/v1/modules/myorg/network/mycloud/versions":
+			fmt.Fprint(w, `{"modules": [{"source": "myorg/network/mycloud", "versions": [{"version": "0.9.0"}, {"version": "0.10.1"}, {"version": "0.10.0"}]}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := server.URL[len("https://"):]
+
+	fs, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/home/user/.terraform.d/credentials.tfrc.json": fmt.Sprintf(`{"credentials": {%q: {"token": "secret"}}}`, host),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	t.Setenv("HOME", "/home/user")
+
+	testcases := []struct {
+		versionsFrom VersionsFrom
+		expected     []string
+	}{
+		{
+			versionsFrom: VersionsFrom{TerraformProvider: TerraformProvider{Host: host, Namespace: "myorg", Type: "mycloud"}},
+			expected:     []string{"1.0.0", "1.1.0", "2.0.0-beta1"},
+		},
+		{
+			versionsFrom: VersionsFrom{TerraformModule: TerraformModule{Host: host, Namespace: "myorg", Name: "network", System: "mycloud"}},
+			expected:     []string{"0.9.0", "0.10.0", "0.10.1"},
+		},
+	}
+
+	for i, tc := range testcases {
+		tracker, err := New(Spec{VersionsFrom: tc.versionsFrom}, HttpGetter(vhttpget.NewWithClient(server.Client())), FS(fs))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		releases, err := tracker.GetReleases()
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		var actual []string
+		for _, r := range releases {
+			actual = append(actual, r.Version)
+		}
+
+		if d := cmp.Diff(tc.expected, actual); d != "" {
+			t.Errorf("#%d: unexpected versions: %s", i, d)
+		}
+	}
+}
+
+func TestProvider_TerraformRegistry_Discovery(t *testing.T) {
+	httpGetter := vhttpget.NewTester(map[string]string{
+		"https://registry.terraform.io/.well-known/terraform.json":          `{"modules.v1": "/v1/modules/", "providers.v1": "/v1/providers/"}`,
+		"https://registry.terraform.io/v1/providers/hashicorp/aws/versions": `{"versions": [{"version": "5.30.0"}, {"version": "5.31.0"}]}`,
+		"https://modules.example.com/.well-known/terraform.json":            `{"modules.v1": "/v1/modules/"}`,
+	})
+
+	tracker, err := New(Spec{VersionsFrom: VersionsFrom{TerraformProvider: TerraformProvider{Namespace: "hashicorp", Type: "aws"}}}, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "5.31.0" {
+		t.Errorf("unexpected version: %s", latest.Version)
+	}
+
+	modulesOnly, err := New(Spec{VersionsFrom: VersionsFrom{TerraformProvider: TerraformProvider{Host: "modules.example.com", Namespace: "hashicorp", Type: "aws"}}}, HttpGetter(httpGetter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := modulesOnly.GetReleases(); err == nil {
+		t.Error("expected error for a registry without providers.v1")
+	}
+}
+
+func TestTerraformToken(t *testing.T) {
+	fs, clean, err := vfst.NewTestFS(map[string]interface{}{
+		"/home/user/.terraform.d/credentials.tfrc.json": `{"credentials": {"app.terraform.io": {"token": "from-file"}, "tf.example.com": {"token": "from-file"}}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	t.Setenv("HOME", "/home/user")
+	t.Setenv("TF_TOKEN_tf_example_com", "from-env")
+	t.Setenv("TF_TOKEN_my__registry_example_com", "from-env")
+
+	tracker, err := New(Spec{}, FS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := map[string]string{
+		"app.terraform.io":        "from-file",
+		"my-registry.example.com": "from-env",
+		"registry.terraform.io":   "",
+		"tf.example.com":          "from-env",
+	}
+
+	for host, expected := range tokens {
+		actual, err := tracker.terraformToken(host)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual != expected {
+			t.Errorf("unexpected token for %s: expected=%q, got=%q", host, expected, actual)
+		}
+	}
+}
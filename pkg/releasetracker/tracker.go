@@ -1,14 +1,18 @@
 package releasetracker
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/PaesslerAG/jsonpath"
 	"github.com/go-logr/logr"
@@ -18,6 +22,7 @@ import (
 	"github.com/variantdev/mod/pkg/depresolver"
 	"github.com/variantdev/mod/pkg/maputil"
 	"github.com/variantdev/mod/pkg/semver"
+	"github.com/variantdev/mod/pkg/versioning"
 	"github.com/variantdev/mod/pkg/vhttpget"
 	"gopkg.in/yaml.v3"
 	"k8s.io/klog/klogr"
@@ -33,10 +38,26 @@ type Release struct {
 	// Version is mostly the original version string obtained from a release provider, with the "v" prefix removed
 	Version string
 
+	// Tag is the original tag the version was extracted from, like "chart/v1.2.3" for "1.2.3".
+	// Empty when the tag is the same as Version.
+	Tag string
+
 	Description string
 
 	// Meta is the provider-specific metadata composed of arbitrary kv pairs
 	Meta map[string]interface{}
+
+	// PublishedAt is the time the release was published at.
+	// Zero if the provider doesn't know it, or it needs to be obtained lazily via publicationTimeGetter.
+	PublishedAt time.Time
+}
+
+// OriginalTag returns the tag the release was obtained from
+func (r *Release) OriginalTag() string {
+	if r.Tag != "" {
+		return r.Tag
+	}
+	return r.Version
 }
 
 type Tracker struct {
@@ -60,7 +81,28 @@ type Tracker struct {
 	// If nil, http.DefaultClient is used.
 	dockerRegistryHTTPClient *http.Client
 
+	// dockerRegistryClient is reused across calls to GetReleases, so that its cache of manifests is reused too
+	dockerRegistryClient *dockerregistry.Client
+
+	// dockerConfig tells how to obtain the credentials for each Docker registry.
+	// If nil, it is read from ~/.docker/config.json
+	dockerConfig *dockerregistry.DockerConfig
+
 	dep *depresolver.Resolver
+
+	// firstSeen is the time each version was first seen by the tracker, used as the fallback of Release.PublishedAt
+	firstSeen map[string]time.Time
+
+	// lockedVersion is the version currently in use. Versions up to it are never held back by MinimumReleaseAge.
+	lockedVersion string
+
+	// provider is the provider that returned the releases last time
+	provider ReleaseProvider
+
+	// ignored is the releases excluded by Spec.Ignore last time
+	ignored []IgnoredRelease
+
+	now func() time.Time
 }
 
 type Option interface {
@@ -94,6 +136,16 @@ func New(conf Spec, opts ...Option) (*Tracker, error) {
 		provider.httpGetter = vhttpget.New()
 	}
 
+	if provider.now == nil {
+		provider.now = time.Now
+	}
+
+	firstSeen := map[string]time.Time{}
+	for v, t := range provider.firstSeen {
+		firstSeen[v] = t
+	}
+	provider.firstSeen = firstSeen
+
 	if provider.AbsWorkDir == "" {
 		path, err := os.Getwd()
 		if err != nil {
@@ -145,9 +197,31 @@ func New(conf Spec, opts ...Option) (*Tracker, error) {
 
 	provider.Spec = conf
 
+	if scheme := conf.VersionsFrom.DefaultVersioningScheme(); provider.Spec.VersionsFrom.Versioning == nil && scheme != "" {
+		provider.Spec.VersionsFrom.Versioning, err = versioning.New(versioning.Spec{Scheme: scheme})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return provider, nil
 }
 
+// DefaultVersioningScheme returns the versioning scheme used when none is specified, which is semver except for
+// the providers whose versions have their own ordering.
+// Versions of Python packages follow PEP 440, and versions of Maven artifacts are ordered with the qualifiers
+// like `-rc1` and `-sp1` as Maven does.
+func (v VersionsFrom) DefaultVersioningScheme() string {
+	switch {
+	case v.PyPIPackage.Name != "":
+		return "pep440"
+	case v.MavenArtifact.ArtifactID != "":
+		return "maven"
+	}
+
+	return ""
+}
+
 func debug(msg string, v ...interface{}) {
 	if os.Getenv("DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, msg+"\n", v...)
@@ -188,10 +262,150 @@ func (p *Tracker) Latest(constraint string) (*Release, error) {
 		return nil, err
 	}
 
-	return getLatest(constraint, all)
+	latest, err := p.LatestOf(constraint, all)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Describe(latest); err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// releaseMetaGetter is implemented by providers that need extra requests to obtain metadata of a release.
+// As it can be costly to do for every release, it is done only for the release to be locked.
+type releaseMetaGetter interface {
+	ReleaseMeta(r *Release) (map[string]interface{}, error)
+}
+
+// Describe adds the metadata that is obtained only for the release to be locked, like the digest of a Docker image, to Release.Meta.
+// The release must be one of the releases returned by the last call to GetReleases.
+func (p *Tracker) Describe(r *Release) error {
+	g, ok := p.provider.(releaseMetaGetter)
+	if !ok {
+		return nil
+	}
+
+	meta, err := g.ReleaseMeta(r)
+	if err != nil {
+		return fmt.Errorf("getting metadata of %s: %w", r.Version, err)
+	}
+
+	if r.Meta == nil {
+		r.Meta = map[string]interface{}{}
+	}
+
+	for k, v := range meta {
+		r.Meta[k] = v
+	}
+
+	return nil
 }
 
-func getLatest(constraint string, all []*Release) (*Release, error) {
+// LatestOf returns the latest release satisfying the constraint out of the releases previously obtained via GetReleases
+func (p *Tracker) LatestOf(constraint string, all []*Release) (*Release, error) {
+	var c *cooldown
+	if p.Spec.MinimumReleaseAge > 0 {
+		c = &cooldown{
+			minimumAge: p.Spec.MinimumReleaseAge,
+			ageOf:      p.releaseAge,
+		}
+	}
+
+	var check func(*Release) (string, error)
+	if rc, ok := p.provider.(releaseChecker); ok {
+		check = rc.Unusable
+	}
+
+	return getLatest(constraint, all, p.Spec.VersionsFrom.Versioning, check, c)
+}
+
+// FirstSeen returns the time each version without the publication time was first seen by the tracker.
+// It includes the versions seen for the first time while finding the latest release with MinimumReleaseAge.
+func (p *Tracker) FirstSeen() map[string]time.Time {
+	r := map[string]time.Time{}
+	for v, t := range p.firstSeen {
+		r[v] = t
+	}
+	return r
+}
+
+// releaseAge returns how long ago the release was published.
+// For a release without the publication time, the time the version was first seen is used instead.
+func (p *Tracker) releaseAge(r *Release) (time.Duration, error) {
+	now := p.now()
+
+	if g, ok := p.provider.(publicationTimeGetter); ok && r.PublishedAt.IsZero() {
+		t, err := g.PublishedAt(r)
+		if err != nil {
+			return 0, fmt.Errorf("getting publication time of %s: %w", r.Version, err)
+		}
+		r.PublishedAt = t
+	}
+
+	if !r.PublishedAt.IsZero() {
+		return now.Sub(r.PublishedAt), nil
+	}
+
+	// We don't know when the versions up to the one in use were published,
+	// but they must have been available for a while, as they've been already adopted.
+	if p.lockedVersion == "" {
+		return maxAge, nil
+	}
+	if c, err := p.versioning().Compare(p.lockedVersion, r.Version); err == nil && c >= 0 {
+		return maxAge, nil
+	}
+
+	seen, ok := p.firstSeen[r.Version]
+	if !ok {
+		seen = now
+		p.firstSeen[r.Version] = seen
+	}
+
+	return now.Sub(seen), nil
+}
+
+const maxAge = time.Duration(1<<63 - 1)
+
+// versioning returns the scheme for parsing and ordering versions of this dependency
+func (p *Tracker) versioning() versioning.Scheme {
+	return versioning.Or(p.Spec.VersionsFrom.Versioning)
+}
+
+// compareReleases compares releases by the versioning scheme, or by semver when the scheme is nil
+func compareReleases(scheme versioning.Scheme, a, b *Release) int {
+	if scheme != nil {
+		if c, err := scheme.Compare(a.Version, b.Version); err == nil {
+			return c
+		}
+	}
+
+	return a.Semver.Compare(b.Semver)
+}
+
+// sortReleases sorts releases from the oldest to the newest
+func sortReleases(rs []*Release, scheme versioning.Scheme) {
+	sort.SliceStable(rs, func(i, j int) bool {
+		return compareReleases(scheme, rs[i], rs[j]) < 0
+	})
+}
+
+// SortReleases sorts releases from the oldest to the newest by the versioning scheme of the tracker
+func (p *Tracker) SortReleases(rs []*Release) {
+	sortReleases(rs, p.Spec.VersionsFrom.Versioning)
+}
+
+type cooldown struct {
+	minimumAge time.Duration
+	ageOf      func(*Release) (time.Duration, error)
+}
+
+// getLatest returns the latest release that satisfies the constraint, ordering releases by the versioning scheme.
+// The constraint is checked against Release.Semver, which is the semver representation of the version in the scheme.
+// check is called for each candidate from the newest one, to skip releases that can't be used for reasons other than the version.
+func getLatest(constraint string, all []*Release, scheme versioning.Scheme, check func(*Release) (string, error), c *cooldown) (*Release, error) {
 	if constraint == "" {
 		constraint = "> 0.0.0-0"
 	}
@@ -203,35 +417,82 @@ func getLatest(constraint string, all []*Release) (*Release, error) {
 
 	debug("releases: %+v", all)
 
-	var latestVer semver.Version
-	var latest *Release
+	var candidates []*Release
 
 	for _, r := range all {
-		if !cons.Check(r.Semver) {
-			continue
+		if cons.Check(r.Semver) {
+			candidates = append(candidates, r)
 		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return compareReleases(scheme, candidates[j], candidates[i]) < 0
+	})
+
+	var tooNew, unusable []string
+
+	for _, r := range candidates {
+		if check != nil {
+			reason, err := check(r)
+			if err != nil {
+				return nil, err
+			}
 
-		if latestVer.LessThan(r.Semver) {
-			latestVer = *r.Semver
-			latest = r
+			if reason != "" {
+				debug("skipping %s: %s", r.Version, reason)
+				unusable = append(unusable, fmt.Sprintf("%s (%s)", r.Semver.String(), reason))
+				continue
+			}
 		}
-	}
 
-	if latest == nil {
-		vers := []string{}
-		for _, r := range all {
-			vers = append(vers, r.Semver.String())
+		if c == nil {
+			return r, nil
 		}
-		return nil, fmt.Errorf("no semver matching %q found in %v", constraint, vers)
+
+		age, err := c.ageOf(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if age >= c.minimumAge {
+			return r, nil
+		}
+
+		tooNew = append(tooNew, r.Semver.String())
 	}
 
-	return latest, nil
+	if len(tooNew) > 0 {
+		return nil, fmt.Errorf("no semver matching %q found that was published at least %s ago: %v are too new", constraint, c.minimumAge, tooNew)
+	}
+
+	if len(unusable) > 0 {
+		return nil, fmt.Errorf("no usable semver matching %q found: %v", constraint, unusable)
+	}
+
+	vers := []string{}
+	for _, r := range all {
+		vers = append(vers, r.Semver.String())
+	}
+	return nil, fmt.Errorf("no semver matching %q found in %v", constraint, vers)
 }
 
 type ReleaseProvider interface {
 	All() ([]*Release, error)
 }
 
+// publicationTimeGetter is implemented by providers that need an extra request per release to know when it was published.
+// It is called only for releases whose ages matter.
+type publicationTimeGetter interface {
+	PublishedAt(r *Release) (time.Time, error)
+}
+
+// releaseChecker is implemented by providers that need an extra request per release to know if it can be used.
+// It is called only for candidates of the latest release, from the newest one until a usable release is found.
+type releaseChecker interface {
+	// Unusable returns why the release can't be used, or an empty string when it can be used
+	Unusable(r *Release) (string, error)
+}
+
 func newExecProvider(cmd string, args []string, r *Tracker) *execProvider {
 	return &execProvider{
 		command: cmd,
@@ -256,12 +517,26 @@ func newGetterProvider(spec GetterJSONPath, r *Tracker) *getterJsonPathProvider
 
 func newDockerHubImageTagsProvider(spec DockerImageTags, r *Tracker) *dockerImageTagsProvider {
 	return &dockerImageTagsProvider{
-		source:  spec.Source,
-		host:    spec.Host,
-		runtime: r,
+		source:    spec.Source,
+		host:      spec.Host,
+		platforms: spec.Platforms,
+		runtime:   r,
 	}
 }
 
+func newOCIArtifactTagsProvider(spec OCIArtifactTags, r *Tracker) *ociArtifactTagsProvider {
+	return &ociArtifactTagsProvider{
+		source:    spec.Source,
+		host:      spec.Host,
+		mediaType: spec.MediaType,
+		runtime:   r,
+	}
+}
+
+// gitHubPaginationParams requests the maximum page size allowed by GitHub API, so that we need the fewest requests
+// to follow all the pages
+var gitHubPaginationParams = map[string]string{"per_page": "100"}
+
 func newGitHubReleasesProvider(spec GitHubReleases, r *Tracker) *httpJsonPathProvider {
 	host := spec.Host
 	if host == "" {
@@ -275,13 +550,25 @@ func newGitHubReleasesProvider(spec GitHubReleases, r *Tracker) *httpJsonPathPro
 		auth = "token " + gt
 	}
 
+	var excludeFlags []string
+	if !spec.IncludePrereleases {
+		excludeFlags = append(excludeFlags, "prerelease")
+	}
+	if !spec.IncludeDrafts {
+		excludeFlags = append(excludeFlags, "draft")
+	}
+
 	return &httpJsonPathProvider{
 		url:           url,
 		authorization: auth,
+		excludeFlags:  excludeFlags,
 		jsonpath:      "$[*].tag_name",
 		metaKey:       "githubRelease",
 		objectPath:    "$[*]",
 		versionPath:   "tag_name",
+		timePath:      "published_at",
+		params:        gitHubPaginationParams,
+		followLinks:   true,
 		runtime:       r,
 	}
 }
@@ -303,6 +590,8 @@ func newGitHubTagsProvider(spec GitHubTags, r *Tracker) *httpJsonPathProvider {
 		url:           url,
 		authorization: auth,
 		jsonpath:      "$[*].name",
+		params:        gitHubPaginationParams,
+		followLinks:   true,
 		runtime:       r,
 	}
 }
@@ -345,21 +634,24 @@ func (p *getterJsonPathProvider) All() ([]*Release, error) {
 }
 
 type dockerImageTagsProvider struct {
-	source   string
-	host     string
-	username string
-	password string
+	source string
+	host   string
+
+	// platforms is the list of platforms that the image of a usable tag must support
+	platforms []string
 
 	runtime *Tracker
+
+	client *dockerregistry.Client
 }
 
 func (p *dockerImageTagsProvider) All() ([]*Release, error) {
-	if p.username == "" {
-		p.username = os.Getenv("DOCKER_USERNAME")
-	}
-	if p.password == "" {
-		p.password = os.Getenv("DOCKER_PASSWORD")
+	for _, s := range p.platforms {
+		if _, err := dockerregistry.ParsePlatform(s); err != nil {
+			return nil, err
+		}
 	}
+
 	w := log.Writer()
 	log.SetOutput(ioutil.Discard)
 	defer log.SetOutput(w)
@@ -367,11 +659,179 @@ func (p *dockerImageTagsProvider) All() ([]*Release, error) {
 	if host == "" {
 		host = "registry.hub.docker.com"
 	}
+	client, err := p.runtime.getDockerRegistryClient(host)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := client.Tags(p.source)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := p.runtime.versionsToReleases(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = client
+
+	return releases, nil
+}
+
+// getDockerRegistryClient returns the client for the registry host, which is reused across calls to GetReleases.
+func (p *Tracker) getDockerRegistryClient(host string) (*dockerregistry.Client, error) {
+	if p.dockerRegistryClient != nil {
+		return p.dockerRegistryClient, nil
+	}
+
+	// DOCKER_USERNAME and DOCKER_PASSWORD apply to every registry for backward compatibility.
+	// Otherwise the credentials for the host are read from the Docker config file.
+	username, password := os.Getenv("DOCKER_USERNAME"), os.Getenv("DOCKER_PASSWORD")
+	if username == "" && password == "" {
+		conf, err := p.getDockerConfig()
+		if err != nil {
+			return nil, err
+		}
+		username, password, err = conf.Credentials(host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var opts []dockerregistry.Option
-	if p.runtime.dockerRegistryHTTPClient != nil {
-		opts = append(opts, dockerregistry.WithHTTPClient(p.runtime.dockerRegistryHTTPClient))
+	if p.dockerRegistryHTTPClient != nil {
+		opts = append(opts, dockerregistry.WithHTTPClient(p.dockerRegistryHTTPClient))
+	}
+	client, err := dockerregistry.New(fmt.Sprintf("https://%s/", host), username, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.dockerRegistryClient = client
+
+	return client, nil
+}
+
+// getDockerConfig returns the Docker config, reading it from the Docker config file if not given.
+// A missing config file is treated as an empty config.
+func (p *Tracker) getDockerConfig() (*dockerregistry.DockerConfig, error) {
+	if p.dockerConfig != nil {
+		return p.dockerConfig, nil
+	}
+
+	path, err := dockerregistry.DockerConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &dockerregistry.DockerConfig{}
+
+	data, err := p.fs.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	} else if err == nil {
+		conf, err = dockerregistry.ParseDockerConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	p.dockerConfig = conf
+
+	return conf, nil
+}
+
+var _ publicationTimeGetter = &dockerImageTagsProvider{}
+
+var _ releaseMetaGetter = &dockerImageTagsProvider{}
+
+// ReleaseMeta returns the content digest of the image as `digest`, so that the image can be referenced by `image:tag@digest`
+func (p *dockerImageTagsProvider) ReleaseMeta(r *Release) (map[string]interface{}, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("unknown tag for version %s", r.Version)
+	}
+
+	d, err := p.client.Digest(p.source, r.OriginalTag())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"digest": d}, nil
+}
+
+var _ releaseChecker = &dockerImageTagsProvider{}
+
+// Unusable tells which of the required platforms the image doesn't support
+func (p *dockerImageTagsProvider) Unusable(r *Release) (string, error) {
+	if len(p.platforms) == 0 {
+		return "", nil
+	}
+
+	if p.client == nil {
+		return "", fmt.Errorf("unknown tag for version %s", r.Version)
 	}
-	client, err := dockerregistry.New(fmt.Sprintf("https://%s/", host), p.username, p.password, opts...)
+
+	supported, err := p.client.Platforms(p.source, r.OriginalTag())
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+
+	for _, s := range p.platforms {
+		want, err := dockerregistry.ParsePlatform(s)
+		if err != nil {
+			return "", err
+		}
+
+		found := false
+		for _, sp := range supported {
+			if sp.Satisfies(want) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, s)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "missing platforms " + strings.Join(missing, ", "), nil
+	}
+
+	return "", nil
+}
+
+// PublishedAt returns the creation time of the image recorded in the image config blob
+func (p *dockerImageTagsProvider) PublishedAt(r *Release) (time.Time, error) {
+	if p.client == nil {
+		return time.Time{}, fmt.Errorf("unknown tag for version %s", r.Version)
+	}
+
+	return p.client.ImageCreated(p.source, r.OriginalTag())
+}
+
+type ociArtifactTagsProvider struct {
+	source string
+	host   string
+
+	// mediaType is the artifact type or the config media type of usable tags
+	mediaType string
+
+	runtime *Tracker
+
+	client *dockerregistry.Client
+}
+
+func (p *ociArtifactTagsProvider) All() ([]*Release, error) {
+	if p.host == "" {
+		return nil, fmt.Errorf("host is required for ociArtifactTags %s", p.source)
+	}
+
+	client, err := p.runtime.getDockerRegistryClient(p.host)
 	if err != nil {
 		return nil, err
 	}
@@ -386,18 +846,99 @@ func (p *dockerImageTagsProvider) All() ([]*Release, error) {
 		return nil, err
 	}
 
+	p.client = client
+
 	return releases, nil
 }
 
+var _ releaseChecker = &ociArtifactTagsProvider{}
+
+// Unusable tells the type of the artifact when it isn't of the media type
+func (p *ociArtifactTagsProvider) Unusable(r *Release) (string, error) {
+	if p.mediaType == "" {
+		return "", nil
+	}
+
+	if p.client == nil {
+		return "", fmt.Errorf("unknown tag for version %s", r.Version)
+	}
+
+	m, err := p.client.Manifest(p.source, r.OriginalTag())
+	if err != nil {
+		return "", err
+	}
+
+	actual := m.ArtifactType
+	if actual == "" && m.Config != nil {
+		actual = m.Config.MediaType
+	}
+
+	if actual != p.mediaType {
+		return "media type " + actual, nil
+	}
+
+	return "", nil
+}
+
+var _ releaseMetaGetter = &ociArtifactTagsProvider{}
+
+// ReleaseMeta returns the content digest of the artifact as `digest`, and the `appVersion` recorded in the config like the one of Helm charts
+func (p *ociArtifactTagsProvider) ReleaseMeta(r *Release) (map[string]interface{}, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("unknown tag for version %s", r.Version)
+	}
+
+	m, err := p.client.Manifest(p.source, r.OriginalTag())
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[string]interface{}{"digest": m.Digest}
+
+	if m.Config == nil || !strings.HasSuffix(m.Config.MediaType, "json") {
+		return meta, nil
+	}
+
+	blob, err := p.client.Config(p.source, r.OriginalTag())
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		AppVersion string `json:"appVersion"`
+	}
+	if err := json.Unmarshal(blob, &config); err != nil {
+		return nil, fmt.Errorf("decoding config of %s:%s: %w", p.source, r.OriginalTag(), err)
+	}
+
+	if config.AppVersion != "" {
+		meta["appVersion"] = config.AppVersion
+	}
+
+	return meta, nil
+}
+
 type httpJsonPathProvider struct {
 	url, jsonpath string
 	authorization string
 	nextpagePath  string
 	params        map[string]string
 
+	// header is the additional request header, like PRIVATE-TOKEN for GitLab API
+	header map[string]string
+
+	// followLinks enables pagination by following the URL with rel="next" in the Link response header
+	followLinks bool
+
+	// excludeFlags is the list of boolean fields of the object at objectPath.
+	// The object is excluded from releases when any of the fields is true.
+	excludeFlags []string
+
 	metaKey     string
 	objectPath  string
 	versionPath string
+	// timePath is the path to the RFC 3339 timestamp of the release in the object at objectPath
+	timePath string
 
 	runtime *Tracker
 }
@@ -478,52 +1019,69 @@ func (p *Tracker) releasesFromHttpJsonPath(pp *httpJsonPathProvider) ([]*Release
 	auth := pp.authorization
 	params := pp.params
 
-	query := ""
-	for k, v := range params {
-		if query != "" {
-			query += "&"
-		}
-		query += k + "=" + v
-	}
-
 	var releases []*Release
+
+	// items is the number of objects found in all the pages, to tell if there were any items but no valid versions
+	var items int
+
 	for url != "" {
-		var u string
-		if strings.Contains(url, query) {
-			u = url
-		} else {
-			if strings.Contains(url, "?") {
-				u = fmt.Sprintf("%s%s", url, query)
-			} else {
-				u = fmt.Sprintf("%s?%s", url, query)
-			}
+		u, err := withQueryParams(url, params)
+		if err != nil {
+			return nil, err
 		}
 		debug("http get: %s", u)
 
 		header := map[string]string{}
+		for k, v := range pp.header {
+			header[k] = v
+		}
 		if auth != "" {
 			header["authorization"] = auth
 		}
 
-		res, err := p.httpGetter.DoRequest(u, vhttpget.Opts{Header: header})
+		res, err := p.httpGetter.Get(u, vhttpget.Opts{Header: header})
 		if err != nil {
 			return nil, err
 		}
 
 		tmp := interface{}(nil)
-		if err := yaml.Unmarshal([]byte(res), &tmp); err != nil {
+		if err := yaml.Unmarshal([]byte(res.Body), &tmp); err != nil {
 			return nil, err
 		}
 
-		debug("http response: %v", res)
+		debug("http response: %v", res.Body)
 
 		if pp.objectPath != "" && pp.versionPath != "" && pp.metaKey != "" {
-			page, err := p.extractObjects(tmp, pp.objectPath, pp.versionPath, pp.metaKey)
+			page, n, err := p.extractObjects(tmp, pp.objectPath, pp.versionPath, pp.metaKey)
 			if err != nil {
 				return nil, err
 			}
 
-			releases = append(releases, page...)
+			items += n
+
+			for _, r := range page {
+				if flag := flaggedAs(r.Meta[pp.metaKey], pp.excludeFlags); flag != "" {
+					p.Logger.V(1).Info("Ignoring release", "version", r.Version, "flag", flag)
+					continue
+				}
+
+				if pp.timePath != "" {
+					t, err := p.extractString(r.Meta[pp.metaKey], pp.timePath)
+					if err != nil {
+						return nil, fmt.Errorf("unable to get timestamp at %s of %s: %v", pp.timePath, r.Version, err)
+					}
+
+					// Draft releases have no publication time
+					if t != "" {
+						r.PublishedAt, err = time.Parse(time.RFC3339, t)
+						if err != nil {
+							return nil, fmt.Errorf("parsing timestamp of %s: %v", r.Version, err)
+						}
+					}
+				}
+
+				releases = append(releases, r)
+			}
 		} else {
 			page, err := p.extractVersions(tmp, jpath)
 			if err != nil {
@@ -533,6 +1091,17 @@ func (p *Tracker) releasesFromHttpJsonPath(pp *httpJsonPathProvider) ([]*Release
 			releases = append(releases, page...)
 		}
 
+		if pp.followLinks {
+			nextUrl, err := res.NextLink()
+			if err != nil {
+				return nil, err
+			}
+
+			url = nextUrl
+
+			continue
+		}
+
 		if nextpagePath == "" {
 			break
 		}
@@ -545,68 +1114,113 @@ func (p *Tracker) releasesFromHttpJsonPath(pp *httpJsonPathProvider) ([]*Release
 		url = nextUrl
 	}
 
+	if pp.objectPath != "" && pp.versionPath != "" && pp.metaKey != "" && len(releases) == 0 {
+		return nil, fmt.Errorf("no valid versions extracted out of %d items at path %q under array at %q", items, pp.versionPath, pp.objectPath)
+	}
+
+	// Releases are sorted per page. Sort them again as a whole
+	sortReleases(releases, p.Spec.VersionsFrom.Versioning)
+
 	return releases, nil
 }
 
-func (p *Tracker) extractObjects(tmp interface{}, objPath, verPath, metaKey string) ([]*Release, error) {
+// flaggedAs returns the first field out of flags that is true in the object
+func flaggedAs(obj interface{}, flags []string) string {
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	for _, f := range flags {
+		if b, ok := m[f].(bool); ok && b {
+			return f
+		}
+	}
+
+	return ""
+}
+
+// withQueryParams appends the params missing in the query of the URL.
+// The existing query is kept as is, without being reordered or escaped again, and the URL is returned as is
+// when it has all the params, like the URL of the next page returned by the API.
+func withQueryParams(rawURL string, params map[string]string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+
+	missing := url.Values{}
+	for k, v := range params {
+		if !q.Has(k) {
+			missing.Set(k, v)
+		}
+	}
+
+	if len(missing) == 0 {
+		return rawURL, nil
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += missing.Encode()
+
+	return u.String(), nil
+}
+
+// extractObjects returns releases extracted from the array of objects at objPath, along with the number of objects in the array.
+// Objects without a valid version are skipped.
+func (p *Tracker) extractObjects(tmp interface{}, objPath, verPath, metaKey string) ([]*Release, int, error) {
 	v, err := maputil.RecursivelyCastKeysToStrings(tmp)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	got, err := jsonpath.Get(objPath, v)
 	if err != nil {
-		return nil, fmt.Errorf("unable to lookup %q in object (%s): %v", objPath, summarizeValue(v), err)
+		return nil, 0, fmt.Errorf("unable to lookup %q in object (%s): %v", objPath, summarizeValue(v), err)
 	}
 
 	var rs []*Release
 
-	var ary []interface{}
+	var n int
 
 	switch typed := got.(type) {
 	case []interface{}:
-		ary = typed
+		n = len(typed)
 
 		for _, obj := range typed {
 			raw, err := jsonpath.Get(verPath, obj)
 			if err != nil {
-				return nil, fmt.Errorf("unable to get version at %s from %s: %v", verPath, summarizeValue(obj), err)
+				return nil, 0, fmt.Errorf("unable to get version at %s from %s: %v", verPath, summarizeValue(obj), err)
 			}
 
 			s, ok := raw.(string)
 			if !ok {
-				return nil, fmt.Errorf("unexpected type of value: want string, got %T, value is %v", raw, raw)
+				return nil, 0, fmt.Errorf("unexpected type of value: want string, got %T, value is %v", raw, raw)
 			}
 
-			v, err := semver.Parse(s)
+			r, err := p.newRelease(s)
 			if err != nil {
 				p.Logger.Info("Ignoring error: parsing semver", "error", err.Error(), "value", s, "jsonPath", verPath)
 				continue
 			}
 
-			meta := map[string]interface{}{
+			r.Meta = map[string]interface{}{
 				metaKey: obj,
 			}
 
-			rs = append(rs, &Release{
-				Semver:  v,
-				Version: strings.TrimPrefix(s, "v"),
-				Meta:    meta,
-			})
+			rs = append(rs, r)
 		}
 	default:
-		return nil, fmt.Errorf("extracting json array at path %q: invalid type of value, %T, found", objPath, typed)
+		return nil, 0, fmt.Errorf("extracting json array at path %q: invalid type of value, %T, found", objPath, typed)
 	}
 
-	if len(rs) == 0 {
-		return nil, fmt.Errorf("no valid versions extracted out of %d items at path %q under array at %q", len(ary), verPath, objPath)
-	}
-
-	sort.Slice(rs, func(i, j int) bool {
-		return rs[i].Semver.LessThan(rs[j].Semver)
-	})
+	sortReleases(rs, p.Spec.VersionsFrom.Versioning)
 
-	return rs, nil
+	return rs, n, nil
 }
 
 func (p *Tracker) extractVersions(tmp interface{}, jpath string) ([]*Release, error) {
@@ -694,23 +1308,17 @@ func (p *Tracker) extractVersionStrings(tmp interface{}, jpath string) ([]string
 func (p *Tracker) versionStringsToReleases(vs []string) ([]*Release, error) {
 	rs := []*Release{}
 	for i, s := range vs {
-		v, err := semver.Parse(s)
+		r, err := p.newRelease(s)
 		if err != nil {
 			e := fmt.Errorf("parsing version: index %d: %q: %v", i, s, err)
 			p.Logger.V(1).Info("ignoring error", "err", e)
+			continue
 		}
 
-		if v != nil {
-			rs = append(rs, &Release{
-				Semver:  v,
-				Version: strings.TrimPrefix(s, "v"),
-			})
-		}
+		rs = append(rs, r)
 	}
 
-	sort.Slice(rs, func(i, j int) bool {
-		return rs[i].Semver.LessThan(rs[j].Semver)
-	})
+	sortReleases(rs, p.Spec.VersionsFrom.Versioning)
 
 	return rs, nil
 }
@@ -724,6 +1332,22 @@ func (p *Tracker) GetProvider() (ReleaseProvider, error) {
 		return newExecProvider(versionsFrom.Exec.Command, versionsFrom.Exec.Args, p), nil
 	} else if versionsFrom.DockerImageTags.Source != "" {
 		return newDockerHubImageTagsProvider(versionsFrom.DockerImageTags, p), nil
+	} else if versionsFrom.OCIArtifactTags.Source != "" {
+		return newOCIArtifactTagsProvider(versionsFrom.OCIArtifactTags, p), nil
+	} else if versionsFrom.HelmChart.Repository != "" {
+		return newHelmChartProvider(versionsFrom.HelmChart, p), nil
+	} else if versionsFrom.GoModule.Path != "" {
+		return newGoModuleProvider(versionsFrom.GoModule, p), nil
+	} else if versionsFrom.NpmPackage.Name != "" {
+		return newNpmPackageProvider(versionsFrom.NpmPackage, p), nil
+	} else if versionsFrom.PyPIPackage.Name != "" {
+		return newPyPIPackageProvider(versionsFrom.PyPIPackage, p), nil
+	} else if versionsFrom.MavenArtifact.ArtifactID != "" {
+		return newMavenArtifactProvider(versionsFrom.MavenArtifact, p), nil
+	} else if versionsFrom.TerraformProvider.Type != "" {
+		return newTerraformProviderProvider(versionsFrom.TerraformProvider, p), nil
+	} else if versionsFrom.TerraformModule.Name != "" {
+		return newTerraformModuleProvider(versionsFrom.TerraformModule, p), nil
 	} else if versionsFrom.GitTags.Source != "" {
 		cmd := fmt.Sprintf("git ls-remote --tags git://%s.git | grep -v { | awk '{ print $2 }' | cut -d'/' -f 3", versionsFrom.GitTags.Source)
 		return newShellProvider(cmd, p), nil
@@ -731,6 +1355,15 @@ func (p *Tracker) GetProvider() (ReleaseProvider, error) {
 		return newGitHubTagsProvider(versionsFrom.GitHubTags, p), nil
 	} else if versionsFrom.GitHubReleases.Source != "" {
 		return newGitHubReleasesProvider(versionsFrom.GitHubReleases, p), nil
+	} else if versionsFrom.GitLabTags.Source != "" {
+		return newGitLabTagsProvider(versionsFrom.GitLabTags, p), nil
+	} else if versionsFrom.GitLabReleases.Source != "" {
+		return newGitLabReleasesProvider(versionsFrom.GitLabReleases, p), nil
+	} else if versionsFrom.GiteaReleases.Source != "" {
+		if versionsFrom.GiteaReleases.Host == "" {
+			return nil, fmt.Errorf("host is required for giteaReleases %s", versionsFrom.GiteaReleases.Source)
+		}
+		return newGiteaReleasesProvider(versionsFrom.GiteaReleases, p), nil
 	}
 	return nil, fmt.Errorf("no versions provider specified")
 }
@@ -746,7 +1379,10 @@ func (p *Tracker) GetReleases() ([]*Release, error) {
 		return nil, err
 	}
 
-	if p.Spec.VersionsFrom.ValidVersionPattern == nil {
+	p.provider = pp
+	p.ignored = nil
+
+	if p.Spec.VersionsFrom.ValidVersionPattern == nil && len(p.Spec.Ignore) == 0 {
 		return all, err
 	}
 
@@ -755,10 +1391,138 @@ func (p *Tracker) GetReleases() ([]*Release, error) {
 	for i := range all {
 		r := all[i]
 
-		if p.Spec.VersionsFrom.ValidVersionPattern.MatchString(r.Version) {
-			filtered = append(filtered, r)
+		if p.Spec.VersionsFrom.ValidVersionPattern != nil && !p.Spec.VersionsFrom.ValidVersionPattern.MatchString(r.Version) {
+			continue
+		}
+
+		rule, err := p.ignoreRuleFor(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule != nil {
+			p.ignored = append(p.ignored, IgnoredRelease{Release: r, Reason: rule.Reason})
+			continue
 		}
+
+		filtered = append(filtered, r)
 	}
 
 	return filtered, nil
 }
+
+// newRelease returns the release for the tag, extracting the version out of the tag with Spec.VersionsFrom.VersionPattern
+func (p *Tracker) newRelease(tag string) (*Release, error) {
+	version := tag
+
+	if pat := p.Spec.VersionsFrom.VersionPattern; pat != nil {
+		m := pat.FindStringSubmatch(tag)
+		if m == nil {
+			return nil, fmt.Errorf("tag %q does not match the version pattern %q", tag, pat.String())
+		}
+		version = m[versionGroupIndex(pat)]
+	}
+
+	version = strings.TrimPrefix(version, "v")
+
+	v, err := p.versioning().Parse(version)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Release{
+		Semver:  v,
+		Version: version,
+	}
+
+	if tag != version {
+		r.Tag = tag
+	}
+
+	return r, nil
+}
+
+// versionGroupIndex returns the index of the capture group named "version", or the first capture group
+func versionGroupIndex(pat *regexp.Regexp) int {
+	if i := pat.SubexpIndex("version"); i > 0 {
+		return i
+	}
+	return 1
+}
+
+// CompileVersionPattern compiles the pattern for VersionsFrom.VersionPattern, which needs a capture group for the version
+func CompileVersionPattern(pattern string) (*regexp.Regexp, error) {
+	pat, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if pat.NumSubexp() == 0 {
+		return nil, fmt.Errorf("version pattern %q has no capture group for the version", pattern)
+	}
+
+	return pat, nil
+}
+
+// IgnoredRelease is a release excluded by an IgnoreRule
+type IgnoredRelease struct {
+	Release *Release
+	Reason  string
+}
+
+// Ignored returns the releases excluded by Spec.Ignore in the last call to GetReleases
+func (p *Tracker) Ignored() []IgnoredRelease {
+	return p.ignored
+}
+
+func (p *Tracker) ignoreRuleFor(r *Release) (*IgnoreRule, error) {
+	for i := range p.Spec.Ignore {
+		rule := p.Spec.Ignore[i]
+
+		matched, err := rule.matches(r, p.versioning())
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			return &rule, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r IgnoreRule) matches(rel *Release, scheme versioning.Scheme) (bool, error) {
+	if strings.TrimPrefix(r.Version, "v") == rel.Version {
+		return true, nil
+	}
+
+	if _, err := scheme.Parse(r.Version); err == nil {
+		c, err := scheme.Compare(r.Version, rel.Version)
+		return err == nil && c == 0, nil
+	}
+
+	cons, err := semver.NewConstraint(r.Version)
+	if err != nil {
+		return false, fmt.Errorf("parsing ignored version %q: %w", r.Version, err)
+	}
+
+	return cons.Check(rel.Semver), nil
+}
+
+// ValidateIgnoreRule returns an error when the version of an ignore rule is neither a version nor a semver range
+func ValidateIgnoreRule(version string) error {
+	if strings.TrimSpace(version) == "" {
+		return fmt.Errorf("version must not be empty")
+	}
+
+	if _, err := semver.Parse(version); err == nil {
+		return nil
+	}
+
+	if _, err := semver.NewConstraint(version); err != nil {
+		return fmt.Errorf("parsing ignored version %q: %w", version, err)
+	}
+
+	return nil
+}
@@ -2,10 +2,12 @@ package releasetracker
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/twpayne/go-vfs"
 	"github.com/variantdev/mod/pkg/cmdsite"
+	"github.com/variantdev/mod/pkg/dockerregistry"
 	"github.com/variantdev/mod/pkg/vhttpget"
 )
 
@@ -101,3 +103,62 @@ func (o *dockerRegistryHTTPClientOption) SetOption(r *Tracker) error {
 	r.dockerRegistryHTTPClient = o.c
 	return nil
 }
+
+// DockerConfig sets how to obtain the credentials for each Docker registry, in place of ~/.docker/config.json.
+func DockerConfig(c *dockerregistry.DockerConfig) Option {
+	return &dockerConfigOption{c: c}
+}
+
+type dockerConfigOption struct {
+	c *dockerregistry.DockerConfig
+}
+
+func (o *dockerConfigOption) SetOption(r *Tracker) error {
+	r.dockerConfig = o.c
+	return nil
+}
+
+// FirstSeen sets the time each version was first seen, which is used in place of the publication time of a release
+// when the provider doesn't know it.
+func FirstSeen(versions map[string]time.Time) Option {
+	return &firstSeenOption{versions: versions}
+}
+
+type firstSeenOption struct {
+	versions map[string]time.Time
+}
+
+func (o *firstSeenOption) SetOption(r *Tracker) error {
+	r.firstSeen = o.versions
+	return nil
+}
+
+// LockedVersion sets the version currently in use.
+// Versions up to the locked version are never held back by the minimum release age.
+func LockedVersion(v string) Option {
+	return &lockedVersionOption{v: v}
+}
+
+type lockedVersionOption struct {
+	v string
+}
+
+func (o *lockedVersionOption) SetOption(r *Tracker) error {
+	r.lockedVersion = o.v
+	return nil
+}
+
+// Now sets the function to obtain the current time, that is used to calculate release ages.
+// This is useful for testing.
+func Now(now func() time.Time) Option {
+	return &nowOption{now: now}
+}
+
+type nowOption struct {
+	now func() time.Time
+}
+
+func (o *nowOption) SetOption(r *Tracker) error {
+	r.now = o.now
+	return nil
+}
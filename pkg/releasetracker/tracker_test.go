@@ -2,12 +2,17 @@ package releasetracker
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
 	"github.com/variantdev/mod/pkg/cmdsite"
+	"github.com/variantdev/mod/pkg/dockerregistry"
+	"github.com/variantdev/mod/pkg/versioning"
 	"github.com/variantdev/mod/pkg/vhttpget"
 	"gopkg.in/yaml.v3"
 )
@@ -19,7 +24,7 @@ func TestGetLatest(t *testing.T) {
 		&Release{Semver: v2},
 		&Release{Semver: v3beta1},
 	}
-	lat, err := getLatest("> 1.0", rels)
+	lat, err := getLatest("> 1.0", rels, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -69,6 +74,111 @@ func TestProvider_Exec(t *testing.T) {
 	}
 }
 
+func TestProvider_Exec_Versioning(t *testing.T) {
+	testcases := []struct {
+		scheme     versioning.Scheme
+		versions   string
+		constraint string
+		expected   string
+	}{
+		{
+			scheme:   versioning.Calver,
+			versions: "2024.03.15\n2024.10.01\n2023.12.31\n",
+			expected: "2024.10.01",
+		},
+		{
+			scheme:     versioning.Calver,
+			versions:   "20240315-abcdef\n20240401-012345\n",
+			constraint: "< 2024.4",
+			expected:   "20240315-abcdef",
+		},
+		{
+			scheme:     versioning.Loose,
+			versions:   "3.12-alpine3.19\n3.12-alpine3.9\n3.13-alpine3.19\n",
+			constraint: "< 3.13",
+			expected:   "3.12-alpine3.19",
+		},
+	}
+
+	for i, tc := range testcases {
+		cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+			cmdsite.NewInput("sh", []string{"-c", "versions"}, map[string]string{}): {Stdout: tc.versions},
+		})
+
+		spec := Spec{VersionsFrom: VersionsFrom{
+			Exec:       Exec{Command: "sh", Args: []string{"-c", "versions"}},
+			Versioning: tc.scheme,
+		}}
+
+		tracker, err := New(spec, Commander(cmdr))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest(tc.constraint)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if latest.Version != tc.expected {
+			t.Errorf("#%d: unexpected version: expected=%v, got=%v", i, tc.expected, latest.Version)
+		}
+	}
+}
+
+func TestProvider_Exec_VersionPattern(t *testing.T) {
+	testcases := []struct {
+		pattern     string
+		expected    string
+		expectedTag string
+	}{
+		{
+			pattern:     `^chart/(.+)$`,
+			expected:    "1.3.0",
+			expectedTag: "chart/v1.3.0",
+		},
+		{
+			pattern:     `^(?P<component>[a-z]+)-(?P<version>[\d.]+)$`,
+			expected:    "1.2.4",
+			expectedTag: "release-1.2.4",
+		},
+	}
+
+	for i, tc := range testcases {
+		cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+			cmdsite.NewInput("sh", []string{"-c", "tags"}, map[string]string{}): {Stdout: "chart/v1.2.3\nchart/v1.3.0\napp/v2.0.0\nrelease-1.2.4\nv3.0.0\n"},
+		})
+
+		pat, err := CompileVersionPattern(tc.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		spec := Spec{VersionsFrom: VersionsFrom{
+			Exec:           Exec{Command: "sh", Args: []string{"-c", "tags"}},
+			VersionPattern: pat,
+		}}
+
+		tracker, err := New(spec, Commander(cmdr))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest("")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if latest.Version != tc.expected || latest.Tag != tc.expectedTag {
+			t.Errorf("#%d: unexpected release: expected=%s (%s), got=%s (%s)", i, tc.expected, tc.expectedTag, latest.Version, latest.Tag)
+		}
+	}
+
+	if _, err := CompileVersionPattern(`^chart/v.+$`); err == nil {
+		t.Error("expected error for a pattern without capture groups")
+	}
+}
+
 func TestProvider_GitTags(t *testing.T) {
 	input := `releaseChannel:
   versionsFrom:
@@ -153,7 +263,7 @@ func TestProvider_GitHubTags(t *testing.T) {
 ]
 `
 	gets := map[string]string{
-		"https://api.github.com/repos/mumoshu/variant/tags": expectedOut,
+		"https://api.github.com/repos/mumoshu/variant/tags?per_page=100": expectedOut,
 	}
 	httpGetter := vhttpget.NewTester(gets)
 	stable, err := New(conf.ReleaseChannel, HttpGetter(httpGetter))
@@ -400,7 +510,7 @@ func TestProvider_GitHubReleases(t *testing.T) {
 `
 
 	gets := map[string]string{
-		"https://api.github.com/repos/mumoshu/variant/releases": expectedOut,
+		"https://api.github.com/repos/mumoshu/variant/releases?per_page=100": expectedOut,
 	}
 	httpGetter := vhttpget.NewTester(gets)
 	stable, err := New(conf.ReleaseChannel, HttpGetter(httpGetter))
@@ -419,9 +529,382 @@ func TestProvider_GitHubReleases(t *testing.T) {
 	}
 }
 
+func TestProvider_GitHubReleases_PrereleasesAndDrafts(t *testing.T) {
+	// v1.1.0-rc.1 is intentionally flagged as a stable release, to verify that the filter relies on the flags from the API
+	// instead of guessing from the semver prerelease part
+	res := `[
+  {"tag_name": "v1.2.0", "draft": true, "prerelease": false},
+  {"tag_name": "v1.1.0", "draft": false, "prerelease": true},
+  {"tag_name": "v1.1.0-rc.1", "draft": false, "prerelease": false},
+  {"tag_name": "v1.0.0", "draft": false, "prerelease": false}
+]
+`
+	gets := map[string]string{
+		"https://api.github.com/repos/mumoshu/variant/releases?per_page=100": res,
+	}
+
+	testcases := []struct {
+		spec     GitHubReleases
+		expected []string
+	}{
+		{
+			spec:     GitHubReleases{},
+			expected: []string{"1.0.0", "1.1.0-rc.1"},
+		},
+		{
+			spec:     GitHubReleases{IncludePrereleases: true},
+			expected: []string{"1.0.0", "1.1.0-rc.1", "1.1.0"},
+		},
+		{
+			spec:     GitHubReleases{IncludeDrafts: true},
+			expected: []string{"1.0.0", "1.1.0-rc.1", "1.2.0"},
+		},
+		{
+			spec:     GitHubReleases{IncludePrereleases: true, IncludeDrafts: true},
+			expected: []string{"1.0.0", "1.1.0-rc.1", "1.1.0", "1.2.0"},
+		},
+	}
+
+	for i, tc := range testcases {
+		spec := tc.spec
+		spec.Source = "mumoshu/variant"
+
+		tracker, err := New(Spec{VersionsFrom: VersionsFrom{GitHubReleases: spec}}, HttpGetter(vhttpget.NewTester(gets)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		releases, err := tracker.GetReleases()
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+
+		var actual []string
+		for _, r := range releases {
+			actual = append(actual, r.Version)
+		}
+
+		if d := cmp.Diff(tc.expected, actual); d != "" {
+			t.Errorf("case %d: unexpected versions: %s", i, d)
+		}
+	}
+}
+
+func TestGetReleases_Ignore(t *testing.T) {
+	res := `[
+  {"tag_name": "v3.13.2"},
+  {"tag_name": "v3.13.1"},
+  {"tag_name": "v3.13.0"},
+  {"tag_name": "v3.12.3"}
+]
+`
+	gets := map[string]string{
+		"https://api.github.com/repos/helm/helm/releases?per_page=100": res,
+	}
+
+	spec := Spec{
+		VersionsFrom: VersionsFrom{GitHubReleases: GitHubReleases{Source: "helm/helm"}},
+		Ignore: []IgnoreRule{
+			{Version: "v3.13.0", Reason: "broken --wait"},
+			{Version: ">= 3.13.1, < 3.13.2"},
+		},
+	}
+
+	tracker, err := New(spec, HttpGetter(vhttpget.NewTester(gets)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("< 3.13.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "3.12.3" {
+		t.Errorf("unexpected latest version: expected 3.12.3, got %s", latest.Version)
+	}
+
+	var ignored []string
+	for _, i := range tracker.Ignored() {
+		ignored = append(ignored, i.Release.Version+":"+i.Reason)
+	}
+
+	if d := cmp.Diff([]string{"3.13.0:broken --wait", "3.13.1:"}, ignored); d != "" {
+		t.Errorf("unexpected ignored releases: %s", d)
+	}
+
+	if err := ValidateIgnoreRule(">= foo"); err == nil {
+		t.Error("expected error for invalid ignore rule")
+	}
+}
+
+func TestProvider_GitHubReleases_MinimumReleaseAge(t *testing.T) {
+	res := `[
+  {"tag_name": "v1.2.0", "published_at": "2020-01-09T00:00:00Z"},
+  {"tag_name": "v1.1.0", "published_at": "2020-01-05T00:00:00Z"},
+  {"tag_name": "v1.0.0", "published_at": "2020-01-01T00:00:00Z"}
+]
+`
+	gets := map[string]string{
+		"https://api.github.com/repos/mumoshu/variant/releases?per_page=100": res,
+	}
+
+	spec := Spec{
+		VersionsFrom: VersionsFrom{
+			GitHubReleases: GitHubReleases{Source: "mumoshu/variant"},
+		},
+		MinimumReleaseAge: 72 * time.Hour,
+	}
+
+	now := func() time.Time {
+		return time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	}
+
+	tracker, err := New(spec, HttpGetter(vhttpget.NewTester(gets)), Now(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.1.0" {
+		t.Errorf("unexpected version: expected=1.1.0, got=%s", latest.Version)
+	}
+
+	if _, err := tracker.Latest("> 1.1.0"); err == nil {
+		t.Error("expected error as the only release matching the constraint is too new, got none")
+	}
+}
+
+func TestGetLatest_MinimumReleaseAge_FirstSeen(t *testing.T) {
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	cmdsiteTester := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "versions"}, map[string]string{}): {Stdout: "1.0.0\n1.1.0\n1.2.0\n"},
+	})
+
+	spec := Spec{
+		VersionsFrom: VersionsFrom{
+			Exec: Exec{Command: "sh", Args: []string{"-c", "versions"}},
+		},
+		MinimumReleaseAge: 72 * time.Hour,
+	}
+
+	tracker, err := New(spec,
+		Commander(cmdsiteTester),
+		Now(func() time.Time { return now }),
+		LockedVersion("1.0.0"),
+		FirstSeen(map[string]time.Time{
+			"1.1.0": now.Add(-96 * time.Hour),
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.1.0" {
+		t.Errorf("unexpected version: expected=1.1.0, got=%s", latest.Version)
+	}
+
+	expected := map[string]time.Time{
+		"1.1.0": now.Add(-96 * time.Hour),
+		"1.2.0": now,
+	}
+	if d := cmp.Diff(expected, tracker.FirstSeen()); d != "" {
+		t.Errorf("unexpected first-seen times: %s", d)
+	}
+}
+
+func TestProvider_GitHubReleases_Pagination(t *testing.T) {
+	var requests []string
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("unexpected per_page: %s", r.URL.RequestURI())
+		}
+
+		var releases []map[string]interface{}
+
+		switch {
+		case r.URL.Path == "/repos/mumoshu/variant/releases":
+			for _, v := range []string{"v0.3.2", "v0.3.1"} {
+				releases = append(releases, map[string]interface{}{"tag_name": v})
+			}
+			// GitHub returns absolute URLs of the canonical repository path in the Link header
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/releases?per_page=100&page=2>; rel="next", <%s/repositories/1/releases?per_page=100&page=3>; rel="last"`, server.URL, server.URL))
+		case r.URL.Path == "/repositories/1/releases" && r.URL.Query().Get("page") == "2":
+			for _, v := range []string{"v0.3.0", "v0.2.0"} {
+				releases = append(releases, map[string]interface{}{"tag_name": v})
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/releases?per_page=100&page=1>; rel="prev", <%s/repositories/1/releases?per_page=100&page=3>; rel="next"`, server.URL, server.URL))
+		case r.URL.Path == "/repositories/1/releases" && r.URL.Query().Get("page") == "3":
+			for _, v := range []string{"v0.1.0", "not-a-version"} {
+				releases = append(releases, map[string]interface{}{"tag_name": v})
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repositories/1/releases?per_page=100&page=2>; rel="prev"`, server.URL))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	conf := Spec{
+		VersionsFrom: VersionsFrom{
+			GitHubReleases: GitHubReleases{
+				Host:   server.URL[len("https://"):],
+				Source: "mumoshu/variant",
+			},
+		},
+	}
+
+	stable, err := New(conf, HttpGetter(vhttpget.NewWithClient(server.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := stable.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, r := range releases {
+		actual = append(actual, r.Version)
+	}
+
+	expected := []string{"0.1.0", "0.2.0", "0.3.0", "0.3.1", "0.3.2"}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("unexpected versions: %s", d)
+	}
+
+	expectedRequests := []string{
+		"/repos/mumoshu/variant/releases?per_page=100",
+		"/repositories/1/releases?per_page=100&page=2",
+		"/repositories/1/releases?per_page=100&page=3",
+	}
+	if d := cmp.Diff(expectedRequests, requests); d != "" {
+		t.Errorf("unexpected requests: %s", d)
+	}
+}
+
+func TestProvider_GitHubTags_Pagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tags []map[string]interface{}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			for i := 100; i > 0; i-- {
+				tags = append(tags, map[string]interface{}{"name": fmt.Sprintf("v1.0.%d", i)})
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/mumoshu/variant/tags?per_page=100&page=2>; rel="next"`, server.URL))
+		case "2":
+			tags = append(tags, map[string]interface{}{"name": "v0.9.0"})
+		default:
+			t.Errorf("unexpected request: %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tags)
+	}))
+	defer server.Close()
+
+	conf := Spec{
+		VersionsFrom: VersionsFrom{
+			GitHubTags: GitHubTags{
+				Host:   server.URL[len("https://"):],
+				Source: "mumoshu/variant",
+			},
+		},
+	}
+
+	stable, err := New(conf, HttpGetter(vhttpget.NewWithClient(server.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := stable.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(releases) != 101 {
+		t.Fatalf("unexpected number of releases: expected=101, got=%d", len(releases))
+	}
+
+	if releases[0].Version != "0.9.0" {
+		t.Errorf("unexpected oldest version: expected=0.9.0, got=%s", releases[0].Version)
+	}
+
+	latest, err := stable.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "1.0.100" {
+		t.Errorf("unexpected version: expected=1.0.100, got=%s", latest.Version)
+	}
+}
+
+func TestProvider_HttpJsonPath_ParamsWithQuery(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The query in the URL must be sent as is, followed by the params
+		if r.URL.RawQuery != "q=v1%2C&order=desc&per_page=100" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name": "v1.0.1"}, {"name": "v1.0.0"}]`))
+	}))
+	defer server.Close()
+
+	tracker, err := New(Spec{}, HttpGetter(vhttpget.NewWithClient(server.Client())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := tracker.releasesFromHttpJsonPath(&httpJsonPathProvider{
+		url:      server.URL + "/tags?q=v1%2C&order=desc",
+		jsonpath: "$[*].name",
+		params:   map[string]string{"per_page": "100", "order": "asc"},
+		runtime:  tracker,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(releases) != 2 {
+		t.Fatalf("unexpected number of releases: expected=2, got=%d", len(releases))
+	}
+}
+
 func TestProvider_DockerRegistryImageTags(t *testing.T) {
 	// Create a TLS test server that mocks the Docker Registry API v2
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/v2/mumoshu/helmfile-chatops/manifests/0.2.0" {
+			w.Header().Set("Docker-Content-Digest", "sha256:0.2.0")
+			return
+		}
 		if r.URL.Path != "/v2/mumoshu/helmfile-chatops/tags/list" {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 			w.WriteHeader(http.StatusNotFound)
@@ -464,4 +947,240 @@ func TestProvider_DockerRegistryImageTags(t *testing.T) {
 	if latest.Version != expected {
 		t.Errorf("unexpected version: expected=%v, got=%v", expected, latest.Version)
 	}
+
+	if d := latest.Meta["digest"]; d != "sha256:0.2.0" {
+		t.Errorf("unexpected digest: expected=sha256:0.2.0, got=%v", d)
+	}
+}
+
+func TestProvider_DockerRegistryImageTags_Platforms(t *testing.T) {
+	requests := map[string]int{}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+
+		list := func(platforms ...string) {
+			m := dockerregistry.Manifest{MediaType: dockerregistry.MediaTypeOCIIndex}
+			for _, s := range platforms {
+				p, _ := dockerregistry.ParsePlatform(s)
+				m.Manifests = append(m.Manifests, dockerregistry.Descriptor{Digest: "sha256:" + s, Platform: &p})
+			}
+			w.Header().Set("Content-Type", dockerregistry.MediaTypeOCIIndex)
+			json.NewEncoder(w).Encode(m)
+		}
+
+		switch r.URL.Path {
+		case "/v2/myorg/myimage/tags/list":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"tags": []string{"0.1.0", "0.2.0", "0.3.0"},
+			})
+		case "/v2/myorg/myimage/manifests/0.1.0":
+			list("linux/amd64", "linux/arm64/v8", "unknown/unknown")
+		case "/v2/myorg/myimage/manifests/0.2.0":
+			list("linux/amd64", "unknown/unknown")
+		case "/v2/myorg/myimage/manifests/0.3.0":
+			w.Header().Set("Content-Type", dockerregistry.MediaTypeDockerManifest)
+			json.NewEncoder(w).Encode(dockerregistry.Manifest{
+				MediaType: dockerregistry.MediaTypeDockerManifest,
+				Config:    &dockerregistry.Descriptor{Digest: "sha256:config"},
+			})
+		case "/v2/myorg/myimage/blobs/sha256:config":
+			w.Write([]byte(`{"architecture":"amd64","os":"linux"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	input := `releaseChannel:
+  versionsFrom:
+    dockerImageTags:
+      source: myorg/myimage
+      host: ` + server.URL[len("https://"):] + `
+      platforms:
+      - linux/amd64
+      - linux/arm64
+`
+
+	conf := &Config{}
+	if err := yaml.Unmarshal([]byte(input), conf); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker, err := New(conf.ReleaseChannel, DockerRegistryHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "0.1.0" {
+		t.Errorf("unexpected version: expected=0.1.0, got=%v", latest.Version)
+	}
+
+	if latest.Meta["digest"] == nil {
+		t.Errorf("expected digest to be set: %v", latest.Meta)
+	}
+
+	if _, err := tracker.Latest("> 0.1.0"); err == nil {
+		t.Error("expected error for no tag supporting all the platforms")
+	}
+
+	// Manifests are fetched once per tag, even though the latest release is computed twice
+	for req, n := range requests {
+		if n != 1 && req != "GET /v2/myorg/myimage/tags/list" {
+			t.Errorf("expected %s to be requested once, but requested %d times", req, n)
+		}
+	}
+}
+
+func TestProvider_DockerRegistryImageTags_Credentials(t *testing.T) {
+	registry := func(username, password string) *httptest.Server {
+		return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Path {
+			case "/v2/myorg/myimage/tags/list":
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"tags": []string{"0.1.0"},
+				})
+			case "/v2/myorg/myimage/manifests/0.1.0":
+				w.Header().Set("Docker-Content-Digest", "sha256:"+username)
+			default:
+				t.Errorf("unexpected path: %s", r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+
+	ghcr := registry("ghuser", "ghpass")
+	defer ghcr.Close()
+
+	ecr := registry("AWS", "ecrpass")
+	defer ecr.Close()
+
+	ghcrHost := ghcr.URL[len("https://"):]
+	ecrHost := ecr.URL[len("https://"):]
+
+	dockerConfig := &dockerregistry.DockerConfig{
+		Auths: map[string]dockerregistry.AuthConfig{
+			ghcrHost: {Username: "ghuser", Password: "ghpass"},
+		},
+		CredHelpers: map[string]string{
+			ecrHost: "ecr-login",
+		},
+		RunHelper: func(helper, serverURL string) ([]byte, error) {
+			if helper != "ecr-login" || serverURL != ecrHost {
+				t.Errorf("unexpected helper call: %s %s", helper, serverURL)
+			}
+			return []byte(`{"Username":"AWS","Secret":"ecrpass"}`), nil
+		},
+	}
+
+	for _, server := range []*httptest.Server{ghcr, ecr} {
+		spec := Spec{VersionsFrom: VersionsFrom{
+			DockerImageTags: DockerImageTags{Source: "myorg/myimage", Host: server.URL[len("https://"):]},
+		}}
+
+		tracker, err := New(spec, DockerRegistryHTTPClient(server.Client()), DockerConfig(dockerConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := tracker.Latest("")
+		if err != nil {
+			t.Fatalf("%s: %v", server.URL, err)
+		}
+
+		if latest.Version != "0.1.0" {
+			t.Errorf("%s: unexpected version: expected=0.1.0, got=%v", server.URL, latest.Version)
+		}
+	}
+}
+
+func TestProvider_OCIArtifactTags(t *testing.T) {
+	const helmConfig = "application/vnd.cncf.helm.config.v1+json"
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		artifact := func(configMediaType, configDigest string) {
+			w.Header().Set("Content-Type", dockerregistry.MediaTypeOCIManifest)
+			w.Header().Set("Docker-Content-Digest", "sha256:"+configDigest)
+			json.NewEncoder(w).Encode(dockerregistry.Manifest{
+				MediaType: dockerregistry.MediaTypeOCIManifest,
+				Config:    &dockerregistry.Descriptor{MediaType: configMediaType, Digest: "sha256:" + configDigest},
+			})
+		}
+
+		switch r.URL.Path {
+		case "/v2/myorg/charts/myapp/tags/list":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"tags": []string{"0.1.0", "0.2.0", "0.3.0"},
+			})
+		case "/v2/myorg/charts/myapp/manifests/0.2.0":
+			artifact(helmConfig, "chart-0.2.0")
+		case "/v2/myorg/charts/myapp/manifests/0.3.0":
+			// An image pushed to the same repository by mistake
+			artifact("application/vnd.oci.image.config.v1+json", "image-0.3.0")
+		case "/v2/myorg/charts/myapp/blobs/sha256:chart-0.2.0":
+			w.Write([]byte(`{"name":"myapp","version":"0.2.0","appVersion":"v1.5.2","apiVersion":"v2"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	input := `releaseChannel:
+  versionsFrom:
+    ociArtifactTags:
+      source: myorg/charts/myapp
+      host: ` + server.URL[len("https://"):] + `
+      mediaType: application/vnd.cncf.helm.config.v1+json
+`
+
+	conf := &Config{}
+	if err := yaml.Unmarshal([]byte(input), conf); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker, err := New(conf.ReleaseChannel, DockerRegistryHTTPClient(server.Client()), DockerConfig(&dockerregistry.DockerConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := tracker.Latest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.Version != "0.2.0" {
+		t.Errorf("unexpected version: expected=0.2.0, got=%v", latest.Version)
+	}
+
+	expectedMeta := map[string]interface{}{
+		"digest":     "sha256:chart-0.2.0",
+		"appVersion": "v1.5.2",
+	}
+	if d := cmp.Diff(expectedMeta, latest.Meta); d != "" {
+		t.Errorf("unexpected meta: %s", d)
+	}
+
+	spec := Spec{VersionsFrom: VersionsFrom{OCIArtifactTags: OCIArtifactTags{Source: "myorg/charts/myapp"}}}
+	noHost, err := New(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := noHost.Latest(""); err == nil {
+		t.Error("expected error for missing host")
+	}
 }
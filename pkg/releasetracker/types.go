@@ -1,6 +1,11 @@
 package releasetracker
 
-import "regexp"
+import (
+	"regexp"
+	"time"
+
+	"github.com/variantdev/mod/pkg/versioning"
+)
 
 type Config struct {
 	ReleaseChannel Spec `yaml:"releaseChannel"`
@@ -8,6 +13,18 @@ type Config struct {
 
 type Spec struct {
 	VersionsFrom VersionsFrom `yaml:"versionsFrom"`
+
+	// MinimumReleaseAge excludes releases published more recently than this duration ago from the latest release
+	MinimumReleaseAge time.Duration `yaml:"minimumReleaseAge"`
+
+	// Ignore excludes the matching releases from the releases returned by the tracker
+	Ignore []IgnoreRule `yaml:"ignore"`
+}
+
+// IgnoreRule matches releases by the exact version or a semver range
+type IgnoreRule struct {
+	Version string `yaml:"version"`
+	Reason  string `yaml:"reason"`
 }
 
 type VersionsFrom struct {
@@ -16,9 +33,28 @@ type VersionsFrom struct {
 	GitTags         GitTags         `yaml:"gitTags"`
 	GitHubTags      GitHubTags      `yaml:"githubTags"`
 	GitHubReleases  GitHubReleases  `yaml:"githubReleases"`
+	GitLabTags      GitLabTags      `yaml:"gitlabTags"`
+	GitLabReleases  GitLabReleases  `yaml:"gitlabReleases"`
+	GiteaReleases   GiteaReleases   `yaml:"giteaReleases"`
 	DockerImageTags DockerImageTags `yaml:"dockerImageTags"`
+	OCIArtifactTags OCIArtifactTags `yaml:"ociArtifactTags"`
+	HelmChart       HelmChart       `yaml:"helmChart"`
+	GoModule        GoModule        `yaml:"goModule"`
+	NpmPackage      NpmPackage      `yaml:"npmPackage"`
+	PyPIPackage     PyPIPackage     `yaml:"pypiPackage"`
+	MavenArtifact   MavenArtifact   `yaml:"mavenArtifact"`
+
+	TerraformProvider TerraformProvider `yaml:"terraformProvider"`
+	TerraformModule   TerraformModule   `yaml:"terraformModule"`
 
 	ValidVersionPattern *regexp.Regexp
+
+	// VersionPattern extracts the version out of each tag, with the capture group named `version` or the first capture group.
+	// Tags not matching the pattern are skipped.
+	VersionPattern *regexp.Regexp
+
+	// Versioning is the scheme for parsing and ordering versions. Defaults to semver when nil
+	Versioning versioning.Scheme `yaml:"-"`
 }
 
 type Exec struct {
@@ -44,9 +80,29 @@ type GitHubTags struct {
 type GitHubReleases struct {
 	Host   string `yaml:"host"`
 	Source string `yaml:"source"`
+
+	// IncludePrereleases includes releases flagged as `prerelease` by GitHub API
+	IncludePrereleases bool `yaml:"includePrereleases"`
+	// IncludeDrafts includes releases flagged as `draft` by GitHub API
+	IncludeDrafts bool `yaml:"includeDrafts"`
 }
 
 type DockerImageTags struct {
 	Host   string `yaml:"host"`
 	Source string `yaml:"source"`
+
+	// Platforms is the list of platforms like `linux/arm64` that the image must support.
+	// Tags whose images don't support all the platforms are skipped.
+	Platforms []string `yaml:"platforms"`
+}
+
+// OCIArtifactTags tracks tags of OCI artifacts like Helm charts stored in OCI registries
+type OCIArtifactTags struct {
+	// Host is the registry host like `ghcr.io`. Unlike DockerImageTags, there is no default
+	Host   string `yaml:"host"`
+	Source string `yaml:"source"`
+
+	// MediaType is the artifact type, or the media type of the config, like `application/vnd.cncf.helm.config.v1+json`.
+	// Tags of artifacts of other types are skipped.
+	MediaType string `yaml:"mediaType"`
 }
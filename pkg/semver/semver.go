@@ -9,6 +9,8 @@ import (
 
 type Version = sv.Version
 
+type Constraints = sv.Constraints
+
 var NewConstraint = sv.NewConstraint
 
 func Parse(s string) (*Version, error) {
@@ -10,7 +10,7 @@ import (
 	"text/template"
 )
 
-func Render(name, text string, data interface{}) (string, error) {
+func funcMap() template.FuncMap {
 	funcs := map[string]interface{}{
 		"hasKey": func(m interface{}, key string) (bool, error) {
 			switch m := m.(type) {
@@ -30,13 +30,44 @@ func Render(name, text string, data interface{}) (string, error) {
 		},
 	}
 
-	funcMap := sprig.TxtFuncMap()
+	m := sprig.TxtFuncMap()
 	for name, f := range funcs {
-		funcMap[name] = f
+		m[name] = f
 	}
 
-	tpl := template.New(name).Option("missingkey=error").Funcs(funcMap)
-	tpl, err := tpl.Parse(text)
+	return m
+}
+
+func newTemplate(name string) *template.Template {
+	return template.New(name).Option("missingkey=error").Funcs(funcMap())
+}
+
+// Parse checks the syntax of the template without rendering it
+func Parse(name, text string) error {
+	_, err := newTemplate(name).Parse(text)
+	return err
+}
+
+// ParseArgs checks the syntax of every template string contained in the arguments
+func ParseArgs(args map[string]interface{}) error {
+	for k, v := range args {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if err := ParseArgs(t); err != nil {
+				return err
+			}
+		case string:
+			if err := Parse(fmt.Sprintf("%s: \"%s\"", k, t), t); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func Render(name, text string, data interface{}) (string, error) {
+	tpl, err := newTemplate(name).Parse(text)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,86 @@
+package variantmod
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/twpayne/go-vfs"
+	"github.com/variantdev/mod/pkg/maputil"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadArguments builds module arguments from `--values` files and `--set` flags.
+// Values files are merged in order, and then `--set key=value` flags are applied on top of them.
+// A dotted key like `image.tag=v1` sets the nested value.
+func LoadArguments(fs vfs.FS, valuesFiles []string, sets []string) (map[string]interface{}, error) {
+	res := Values{}
+
+	for _, f := range valuesFiles {
+		bytes, err := fs.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading values file %q: %w", f, err)
+		}
+
+		vals := map[string]interface{}{}
+		if err := yaml.Unmarshal(bytes, &vals); err != nil {
+			return nil, fmt.Errorf("parsing values file %q: %w", f, err)
+		}
+
+		vals, err = maputil.CastKeysToStrings(vals)
+		if err != nil {
+			return nil, fmt.Errorf("parsing values file %q: %w", f, err)
+		}
+
+		res = mergeByOverwrite(res, vals)
+	}
+
+	for _, kv := range sets {
+		vals, err := parseSet(kv)
+		if err != nil {
+			return nil, err
+		}
+
+		res = mergeByOverwrite(res, vals)
+	}
+
+	return res, nil
+}
+
+func parseSet(kv string) (map[string]interface{}, error) {
+	pair := strings.SplitN(kv, "=", 2)
+	if len(pair) != 2 || pair[0] == "" {
+		return nil, fmt.Errorf("invalid --set %q: must be in the form of KEY=VALUE", kv)
+	}
+
+	keys := strings.Split(pair[0], ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid --set %q: key must not contain empty segments", kv)
+		}
+	}
+
+	var v interface{} = parseSetValue(pair[1])
+	for i := len(keys) - 1; i >= 0; i-- {
+		v = map[string]interface{}{keys[i]: v}
+	}
+
+	return v.(map[string]interface{}), nil
+}
+
+// parseSetValue converts integers and booleans so that they can satisfy `type: integer` and `type: boolean` in the schema.
+// Anything else, including floats like `1.10`, is kept as a string, as it is likely to be a version number.
+func parseSetValue(s string) interface{} {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return s
+}
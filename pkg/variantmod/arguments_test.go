@@ -0,0 +1,180 @@
+package variantmod
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestLoadArguments(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/values1.yaml": `
+image:
+  repository: myapp
+  tag: v1
+replicas: 1
+`,
+		"/path/to/values2.yaml": `
+image:
+  tag: v2
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+
+	args, err := LoadArguments(fs, []string{"/path/to/values1.yaml", "/path/to/values2.yaml"}, []string{"image.tag=v3", "replicas=3", "debug=true", "kubeVersion=1.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"image": Values{
+			"repository": "myapp",
+			"tag":        "v3",
+		},
+		"replicas":    3,
+		"debug":       true,
+		"kubeVersion": "1.10",
+	}
+
+	if diff := cmp.Diff(expected, args); diff != "" {
+		t.Errorf("unexpected arguments: %s", diff)
+	}
+
+	for _, invalid := range []string{"foo", "=bar", "foo..bar=baz"} {
+		if _, err := LoadArguments(fs, nil, []string{invalid}); err == nil {
+			t.Errorf("expected error for --set %q, got none", invalid)
+		}
+	}
+}
+
+func TestBuild_Arguments(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    properties:
+      replicas:
+        type: integer
+  defaults:
+    namespace: default
+    replicas: 1
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        namespace: "{{.namespace}}"
+        replicas: "{{.replicas}}"
+`,
+		"/path/to/src.yaml.tpl": "namespace: {{.namespace}}\nreplicas: {{.replicas}}\n",
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), Arguments(map[string]interface{}{"replicas": 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := man.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := fs.ReadFile("/path/to/dst.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "namespace: default\nreplicas: 3\n"
+	if d := cmp.Diff(expected, string(actual)); d != "" {
+		t.Errorf("unexpected dst.yaml: %s", d)
+	}
+
+	man, err = New(Logger(log), FS(fs), WD("/path/to"), Arguments(map[string]interface{}{"replicas": "three"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = man.Build()
+	if err == nil || !strings.Contains(err.Error(), `/replicas: Invalid type. Expected: integer, given: string (actual value: "three")`) {
+		t.Errorf("expected schema violation, got %v", err)
+	}
+}
+
+func TestArguments_SchemaViolation(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    properties:
+      replicas:
+        type: integer
+  defaults:
+    replicas: 1
+
+dependencies:
+  alpine:
+    releasesFrom:
+      exec:
+        command: echo
+        args: ["3.10.0"]
+    version: "> 3.9"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), Arguments(map[string]interface{}{"replicas": "three"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violation := `/replicas: Invalid type. Expected: integer, given: string (actual value: "three")`
+
+	// Every command accepting --set and --values rejects the invalid arguments, not only build
+	commands := map[string]func() error{
+		"exec": func() error {
+			_, err := man.Shell()
+			return err
+		},
+		"list-dependency-versions": func() error {
+			return man.ListVersions("alpine", &strings.Builder{})
+		},
+		"up": func() error {
+			return man.Up()
+		},
+		"validate": func() error {
+			return man.Validate()
+		},
+	}
+
+	for name, run := range commands {
+		if err := run(); err == nil || !strings.Contains(err.Error(), violation) {
+			t.Errorf("%s: expected schema violation, got %v", name, err)
+		}
+	}
+
+	if _, err := fs.ReadFile("/path/to/variant.lock"); err == nil {
+		t.Error("variant.lock should not be written when the arguments are invalid")
+	}
+}
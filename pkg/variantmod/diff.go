@@ -0,0 +1,180 @@
+package variantmod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/variantdev/mod/pkg/overlayfs"
+)
+
+const diffContextLines = 3
+
+type FileChange struct {
+	Path string `json:"path"`
+	// Status is either "added" or "modified"
+	Status string `json:"status"`
+	Diff   string `json:"diff"`
+}
+
+func (m *ModuleManager) fileChanges(changes []overlayfs.Change) []FileChange {
+	var r []FileChange
+
+	for _, c := range changes {
+		if c.Existed && bytes.Equal(c.Before, c.After) {
+			continue
+		}
+
+		path := c.Path
+		if rel, err := filepath.Rel(m.AbsWorkDir, c.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+
+		status := "modified"
+		if !c.Existed {
+			status = "added"
+		}
+
+		r = append(r, FileChange{
+			Path:   path,
+			Status: status,
+			Diff:   unifiedDiff(path, c.Existed, string(c.Before), string(c.After)),
+		})
+	}
+
+	return r
+}
+
+// WriteDiff writes the unified diff of every file that would be changed by the build
+func (r *BuildResult) WriteDiff(out io.Writer) error {
+	for _, c := range r.Changes {
+		if _, err := io.WriteString(out, c.Diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *BuildResult) WriteJSON(out io.Writer) error {
+	changes := r.Changes
+	if changes == nil {
+		changes = []FileChange{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func unifiedDiff(path string, existed bool, before, after string) string {
+	var lines []diffLine
+	for _, c := range diff.DiffChunks(splitLines(before), splitLines(after)) {
+		for _, l := range c.Deleted {
+			lines = append(lines, diffLine{'-', l})
+		}
+		for _, l := range c.Added {
+			lines = append(lines, diffLine{'+', l})
+		}
+		for _, l := range c.Equal {
+			lines = append(lines, diffLine{' ', l})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+
+	from := "a/" + path
+	if !existed {
+		from = "/dev/null"
+	}
+	fmt.Fprintf(buf, "--- %s\n+++ b/%s\n", from, path)
+
+	// aLine and bLine are the 1-indexed line numbers in before and after, at lines[i]
+	aLine, bLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk until we see more than twice the context lines of unchanged lines in a row
+		end := i
+		for unchanged := 0; end < len(lines) && unchanged <= 2*diffContextLines; end++ {
+			if lines[end].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for end > i && lines[end-1].op == ' ' {
+			end--
+		}
+		hunkEnd := end + diffContextLines
+		if hunkEnd > len(lines) {
+			hunkEnd = len(lines)
+		}
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		var aCount, bCount int
+		hunk := &bytes.Buffer{}
+		for _, l := range lines[start:hunkEnd] {
+			switch l.op {
+			case '-':
+				aCount++
+			case '+':
+				bCount++
+			default:
+				aCount++
+				bCount++
+			}
+			fmt.Fprintf(hunk, "%c%s\n", l.op, l.text)
+		}
+
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		buf.Write(hunk.Bytes())
+
+		for _, l := range lines[i:hunkEnd] {
+			if l.op != '+' {
+				aLine++
+			}
+			if l.op != '-' {
+				bLine++
+			}
+		}
+		i = hunkEnd
+	}
+
+	return buf.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range starts at the line just before the hunk, as GNU diff does
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
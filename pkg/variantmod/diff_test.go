@@ -0,0 +1,118 @@
+package variantmod
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/twpayne/go-vfs/vfst"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestDiff(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  defaults:
+    foo: FOO
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        foo: "{{.foo}}"
+  textReplace:
+    Dockerfile:
+      from: "FROM alpine:3.9"
+      to: "FROM alpine:3.10"
+`,
+		"/path/to/src.yaml.tpl": "foo: {{.foo}}\n",
+		"/path/to/Dockerfile": `FROM alpine:3.9
+
+RUN echo 1
+RUN echo 2
+RUN echo 3
+RUN echo 4
+RUN echo 5
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := man.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteDiff(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,4 +1,4 @@
+-FROM alpine:3.9
++FROM alpine:3.10
+ 
+ RUN echo 1
+ RUN echo 2
+--- /dev/null
++++ b/dst.yaml
+@@ -0,0 +1 @@
++foo: FOO
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected diff: expected=\n%s\ngot=\n%s", expected, buf.String())
+	}
+
+	if _, err := fs.Stat("/path/to/dst.yaml"); !os.IsNotExist(err) {
+		t.Errorf("dry run must not write files: %v", err)
+	}
+
+	dockerfile, err := fs.ReadFile("/path/to/Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dockerfile) != files["/path/to/Dockerfile"] {
+		t.Errorf("dry run must not modify files: got=%s", string(dockerfile))
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	after := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+	expected := `--- a/f.txt
++++ b/f.txt
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
+ 15
++16
+`
+	if got := unifiedDiff("f.txt", true, before, after); got != expected {
+		t.Errorf("unexpected diff: expected=\n%s\ngot=\n%s", expected, got)
+	}
+}
@@ -0,0 +1,132 @@
+package variantmod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/cmdsite"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestIgnore(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  helm:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - helm-versions
+    version: "> 3.0"
+    ignore:
+    - version: 3.13.0
+      reason: "--wait never returns"
+    - version: ">= 3.13.1, < 3.13.2"
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  helm:
+    version: "3.12.3"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "helm-versions"}, map[string]string{}): {Stdout: "3.12.3\n3.13.0\n3.13.1\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := man.ListVersions("helm", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOut := "3.12.3\t\n3.13.0\t(ignored: --wait never returns)\n3.13.1\t(ignored)\n"
+	if d := cmp.Diff(expectedOut, out.String()); d != "" {
+		t.Errorf("unexpected output of list-dependency-versions: %s", d)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mod, err := man.loadLockAndModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := mod.VersionLock.Dependencies["helm"].Version; v != "3.12.3" {
+		t.Errorf("expected helm to stay at 3.12.3, got %s", v)
+	}
+
+	expectedNote := "\n\nIgnored versions:\n- `helm` 3.13.0: --wait never returns\n- `helm` >= 3.13.1, < 3.13.2\n"
+	if d := cmp.Diff(expectedNote, mod.ignoreNote()); d != "" {
+		t.Errorf("unexpected pull request note: %s", d)
+	}
+}
+
+func TestIgnore_ListVersionsOrder(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  helm:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - helm-versions
+    version: "> 3.0"
+    ignore:
+    - version: 3.13.0
+      reason: "--wait never returns"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "helm-versions"}, map[string]string{}): {Stdout: "3.14.0\n3.13.0\n3.12.10\n3.12.3\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := man.ListVersions("helm", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "3.12.3\t\n3.12.10\t\n3.13.0\t(ignored: --wait never returns)\n3.14.0\t\n"
+	if d := cmp.Diff(expected, out.String()); d != "" {
+		t.Errorf("unexpected output of list-dependency-versions: %s", d)
+	}
+}
@@ -2,8 +2,10 @@ package variantmod
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/k-kinzal/aliases/pkg/aliases/yaml"
@@ -14,6 +16,7 @@ import (
 	"github.com/variantdev/mod/pkg/depresolver"
 	"github.com/variantdev/mod/pkg/execversionmanager"
 	"github.com/variantdev/mod/pkg/releasetracker"
+	"github.com/variantdev/mod/pkg/versioning"
 )
 
 func NewLoaderFromManager(man *ModuleManager) *ModuleLoader {
@@ -24,6 +27,8 @@ func NewLoaderFromManager(man *ModuleManager) *ModuleLoader {
 		AbsWorkDir:         man.AbsWorkDir,
 		GoGetterAbsWorkDir: man.goGetterAbsWorkDir,
 		dep:                man.dep,
+
+		DockerRegistryHTTPClient: man.dockerRegistryHTTPClient,
 	}
 }
 
@@ -35,6 +40,9 @@ type ModuleLoader struct {
 	AbsWorkDir         string
 	GoGetterAbsWorkDir string
 
+	// DockerRegistryHTTPClient is the optional HTTP client used by the `dockerImageTags` releases provider
+	DockerRegistryHTTPClient *http.Client
+
 	dep *depresolver.Resolver
 }
 
@@ -85,6 +93,70 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 				return nil, err
 			}
 			r.VersionsFrom.DockerImageTags.Host = dep.VersionsFrom.DockerImageTags.Host
+			r.VersionsFrom.DockerImageTags.Platforms = dep.VersionsFrom.DockerImageTags.Platforms
+		}
+		if dep.VersionsFrom.OCIArtifactTags.Source != nil {
+			r.VersionsFrom.OCIArtifactTags.Source, err = dep.VersionsFrom.OCIArtifactTags.Source(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.OCIArtifactTags.Host = dep.VersionsFrom.OCIArtifactTags.Host
+			r.VersionsFrom.OCIArtifactTags.MediaType = dep.VersionsFrom.OCIArtifactTags.MediaType
+		}
+		if dep.VersionsFrom.HelmChart.Repository != nil {
+			r.VersionsFrom.HelmChart.Repository, err = dep.VersionsFrom.HelmChart.Repository(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.HelmChart.Chart = dep.VersionsFrom.HelmChart.Chart
+		}
+		if dep.VersionsFrom.GoModule.Path != nil {
+			r.VersionsFrom.GoModule.Path, err = dep.VersionsFrom.GoModule.Path(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.GoModule.IncludeIncompatible = dep.VersionsFrom.GoModule.IncludeIncompatible
+		}
+		if dep.VersionsFrom.NpmPackage.Name != nil {
+			r.VersionsFrom.NpmPackage.Name, err = dep.VersionsFrom.NpmPackage.Name(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.NpmPackage.Registry = dep.VersionsFrom.NpmPackage.Registry
+			r.VersionsFrom.NpmPackage.DistTag = dep.VersionsFrom.NpmPackage.DistTag
+		}
+		if dep.VersionsFrom.PyPIPackage.Name != nil {
+			r.VersionsFrom.PyPIPackage.Name, err = dep.VersionsFrom.PyPIPackage.Name(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.PyPIPackage.Index = dep.VersionsFrom.PyPIPackage.Index
+		}
+		if dep.VersionsFrom.MavenArtifact.ArtifactID != nil {
+			r.VersionsFrom.MavenArtifact.ArtifactID, err = dep.VersionsFrom.MavenArtifact.ArtifactID(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.MavenArtifact.Repository = dep.VersionsFrom.MavenArtifact.Repository
+			r.VersionsFrom.MavenArtifact.GroupID = dep.VersionsFrom.MavenArtifact.GroupID
+			r.VersionsFrom.MavenArtifact.ServerID = dep.VersionsFrom.MavenArtifact.ServerID
+		}
+		if dep.VersionsFrom.TerraformProvider.Type != nil {
+			r.VersionsFrom.TerraformProvider.Type, err = dep.VersionsFrom.TerraformProvider.Type(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.TerraformProvider.Host = dep.VersionsFrom.TerraformProvider.Host
+			r.VersionsFrom.TerraformProvider.Namespace = dep.VersionsFrom.TerraformProvider.Namespace
+		}
+		if dep.VersionsFrom.TerraformModule.Name != nil {
+			r.VersionsFrom.TerraformModule.Name, err = dep.VersionsFrom.TerraformModule.Name(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.TerraformModule.Host = dep.VersionsFrom.TerraformModule.Host
+			r.VersionsFrom.TerraformModule.Namespace = dep.VersionsFrom.TerraformModule.Namespace
+			r.VersionsFrom.TerraformModule.System = dep.VersionsFrom.TerraformModule.System
 		}
 		if dep.VersionsFrom.GitHubReleases.Source != nil {
 			r.VersionsFrom.GitHubReleases.Source, err = dep.VersionsFrom.GitHubReleases.Source(initialValues)
@@ -92,6 +164,8 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 				return nil, err
 			}
 			r.VersionsFrom.GitHubReleases.Host = dep.VersionsFrom.GitHubReleases.Host
+			r.VersionsFrom.GitHubReleases.IncludePrereleases = dep.VersionsFrom.GitHubReleases.IncludePrereleases
+			r.VersionsFrom.GitHubReleases.IncludeDrafts = dep.VersionsFrom.GitHubReleases.IncludeDrafts
 		}
 		if dep.VersionsFrom.GitHubTags.Source != nil {
 			r.VersionsFrom.GitHubTags.Source, err = dep.VersionsFrom.GitHubTags.Source(initialValues)
@@ -100,6 +174,30 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 			}
 			r.VersionsFrom.GitHubTags.Host = dep.VersionsFrom.GitHubTags.Host
 		}
+		if dep.VersionsFrom.GitLabReleases.Source != nil {
+			r.VersionsFrom.GitLabReleases.Source, err = dep.VersionsFrom.GitLabReleases.Source(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.GitLabReleases.BaseURL = dep.VersionsFrom.GitLabReleases.BaseURL
+		}
+		if dep.VersionsFrom.GitLabTags.Source != nil {
+			r.VersionsFrom.GitLabTags.Source, err = dep.VersionsFrom.GitLabTags.Source(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.GitLabTags.BaseURL = dep.VersionsFrom.GitLabTags.BaseURL
+		}
+		if dep.VersionsFrom.GiteaReleases.Source != nil {
+			r.VersionsFrom.GiteaReleases.Source, err = dep.VersionsFrom.GiteaReleases.Source(initialValues)
+			if err != nil {
+				return nil, err
+			}
+			r.VersionsFrom.GiteaReleases.Host = dep.VersionsFrom.GiteaReleases.Host
+			r.VersionsFrom.GiteaReleases.Token = dep.VersionsFrom.GiteaReleases.Token
+			r.VersionsFrom.GiteaReleases.IncludePrereleases = dep.VersionsFrom.GiteaReleases.IncludePrereleases
+			r.VersionsFrom.GiteaReleases.IncludeDrafts = dep.VersionsFrom.GiteaReleases.IncludeDrafts
+		}
 		if dep.VersionsFrom.GitTags.Source != nil {
 			r.VersionsFrom.GitTags.Source, err = dep.VersionsFrom.GitTags.Source(initialValues)
 			if err != nil {
@@ -123,6 +221,31 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 			r.VersionsFrom.ValidVersionPattern = validVerPattern
 		}
 
+		if dep.VersionsFrom.VersionPattern != "" {
+			r.VersionsFrom.VersionPattern, err = releasetracker.CompileVersionPattern(dep.VersionsFrom.VersionPattern)
+			if err != nil {
+				return nil, fmt.Errorf("dependency %q: %w", alias, err)
+			}
+		}
+
+		versioningSpec := dep.VersionsFrom.Versioning
+		if versioningSpec.Scheme == "" {
+			versioningSpec.Scheme = r.VersionsFrom.DefaultVersioningScheme()
+		}
+
+		r.VersionsFrom.Versioning, err = versioning.New(versioningSpec)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", alias, err)
+		}
+
+		r.MinimumReleaseAge = mod.Dependencies[alias].MinimumReleaseAge
+
+		for _, ig := range mod.Dependencies[alias].Ignore {
+			r.Ignore = append(r.Ignore, releasetracker.IgnoreRule{Version: ig.Version, Reason: ig.Reason})
+		}
+
+		locked := verLock.Dependencies[alias]
+
 		var rc *releasetracker.Tracker
 		rc, err = releasetracker.New(
 			r,
@@ -130,6 +253,9 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 			releasetracker.GoGetterWD(m.GoGetterAbsWorkDir),
 			releasetracker.FS(m.FS),
 			releasetracker.Commander(m.RunCommand),
+			releasetracker.LockedVersion(locked.Version),
+			releasetracker.FirstSeen(locked.FirstSeen),
+			releasetracker.DockerRegistryHTTPClient(m.DockerRegistryHTTPClient),
 		)
 		if err != nil {
 			return nil, err
@@ -138,8 +264,20 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 		trackers[alias] = rc
 	}
 
+	forceUpdate := map[string]bool{}
+	for _, alias := range params.ForceUpdateOnly {
+		dep, ok := mod.Dependencies[alias]
+		if !ok || dep.Kind == "Module" {
+			return nil, fmt.Errorf("unable to update %q: no such dependency", alias)
+		}
+		forceUpdate[alias] = true
+	}
+
 	submods := map[string]*Module{}
 
+	constraints := map[string]string{}
+	digestChanges := map[string]DigestChange{}
+
 	// Resolve versions of dependencies
 	for alias, dep := range mod.Dependencies {
 		if dep.Kind == "Module" {
@@ -147,19 +285,48 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 		}
 
 		preUp, ok := verLock.Dependencies[alias]
+
+		var scheme versioning.Scheme
+		if t, ok := trackers[alias]; ok {
+			scheme = t.Spec.VersionsFrom.Versioning
+		}
+
+		constraint, err := updatePolicyConstraint(dep.VersionConstraint, dep.UpdatePolicy, preUp.Version, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", alias, err)
+		}
+
+		constraints[alias] = constraint
 		if ok {
-			if params.ForceUpdate {
+			if preUp.Pinned {
+				m.Logger.V(2).Info("tracker unused. dependency is pinned", "alias", alias, "version", preUp.Version)
+			} else if params.ForceUpdate && (len(forceUpdate) == 0 || forceUpdate[alias]) {
 				m.Logger.V(2).Info("finding tracker", "alias", alias, "trackers", trackers)
 				tracker, ok := trackers[alias]
 				if ok {
 					m.Logger.V(2).Info("tracker found", "alias", alias)
-					rel, err := tracker.Latest(dep.VersionConstraint)
+					rel, err := tracker.Latest(constraint)
 					if err != nil {
 						return nil, fmt.Errorf("resolving dependency %q: %w", alias, err)
 					}
 
+					firstSeen := firstSeenAfter(tracker.FirstSeen(), rel.Version, tracker.Spec.VersionsFrom.Versioning)
+
 					if preUp.Version == rel.Version {
-						m.Logger.V(2).Info("No update found", "alias", alias)
+						if prev, cur := preUp.Meta["digest"], rel.Meta["digest"]; prev != nil && cur != nil && prev != cur {
+							// The same tag has been pushed again with a different content
+							m.Logger.Info("Digest changed", "alias", alias, "version", rel.Version, "previousDigest", prev, "digest", cur)
+							digestChanges[alias] = DigestChange{
+								Version:  rel.Version,
+								Previous: fmt.Sprint(prev),
+								Current:  fmt.Sprint(cur),
+							}
+							preUp.Meta = rel.Meta
+						} else {
+							m.Logger.V(2).Info("No update found", "alias", alias)
+						}
+						preUp.FirstSeen = firstSeen
+						verLock.Dependencies[alias] = preUp
 						continue
 					}
 
@@ -168,8 +335,10 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 					verLock.Dependencies[alias] = confapi.DependencyState{
 						Version:         rel.Version,
 						PreviousVersion: prev,
+						Tag:             rel.Tag,
 						Meta:            rel.Meta,
 						Versions:        preUp.Versions,
+						FirstSeen:       firstSeen,
 					}
 				} else {
 					m.Logger.V(2).Info("no tracker found", "alias", alias)
@@ -182,13 +351,14 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 			tracker, ok := trackers[alias]
 			if ok {
 				m.Logger.V(2).Info("tracker found", "alias", alias)
-				rel, err := tracker.Latest(dep.VersionConstraint)
+				rel, err := tracker.Latest(constraint)
 				if err != nil {
 					return nil, fmt.Errorf("updating locked dependency %q: %w", alias, err)
 				}
 
 				verLock.Dependencies[alias] = confapi.DependencyState{
 					Version: rel.Version,
+					Tag:     rel.Tag,
 					Meta:    rel.Meta,
 				}
 			} else {
@@ -287,6 +457,7 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 		Alias:           mod.Name,
 		Values:          latestValues,
 		ValuesSchema:    mod.ValuesSchema,
+		Parameters:      mergeByOverwrite(Values{}, mod.Defaults, params.Arguments),
 		Files:           mod.Files,
 		Directories:     mod.Directories,
 		TextReplaces:    mod.TextReplaces,
@@ -297,6 +468,9 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 		ReleaseTrackers: trackers,
 		VersionLock:     verLock,
 		Stages:          mod.Stages,
+
+		VersionConstraints: constraints,
+		DigestChanges:      digestChanges,
 	}
 
 	if err := r.Transact(func(t *deploycoordinator.Single) error {
@@ -325,3 +499,26 @@ func (m *ModuleLoader) InitModule(params confapi.ModuleParams, mod confapi.Modul
 
 	return r, nil
 }
+
+// firstSeenAfter returns the first-seen times of versions newer than the version, as older ones no longer matter
+func firstSeenAfter(seen map[string]time.Time, version string, scheme versioning.Scheme) map[string]time.Time {
+	scheme = versioning.Or(scheme)
+
+	if _, err := scheme.Parse(version); err != nil {
+		return seen
+	}
+
+	var r map[string]time.Time
+	for v, t := range seen {
+		c, err := scheme.Compare(v, version)
+		if err != nil || c <= 0 {
+			continue
+		}
+		if r == nil {
+			r = map[string]time.Time{}
+		}
+		r[v] = t
+	}
+
+	return r
+}
@@ -2,6 +2,7 @@ package variantmod
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -43,6 +44,20 @@ func HCLModuleAsConfModule(mod hclconf.Module) (*confapi.Module, error) {
 			provider.ValidVersionPattern = *d.ValidVersionPattern
 		}
 
+		if d.VersionPattern != nil {
+			provider.VersionPattern = *d.VersionPattern
+		}
+
+		if v := d.Versioning; v != nil {
+			provider.Versioning.Scheme = v.Scheme
+			if v.Pattern != nil {
+				provider.Versioning.Pattern = *v.Pattern
+			}
+			if v.SortBy != nil {
+				provider.Versioning.SortBy = *v.SortBy
+			}
+		}
+
 		switch d.Type {
 		case "exec":
 			var e hclconf.ExecDependency
@@ -92,22 +107,190 @@ func HCLModuleAsConfModule(mod hclconf.Module) (*confapi.Module, error) {
 				Source: func(_ map[string]interface{}) (string, error) {
 					return e.Source, nil
 				},
+				IncludePrereleases: e.IncludePrereleases != nil && *e.IncludePrereleases,
+				IncludeDrafts:      e.IncludeDrafts != nil && *e.IncludeDrafts,
+			}
+		case "gitlab_tag":
+			var e hclconf.GitLabTags
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			var baseURL string
+			if e.BaseURL != nil {
+				baseURL = *e.BaseURL
+			}
+			provider.GitLabTags = confapi.GitLabTags{
+				BaseURL: baseURL,
+				Source: func(_ map[string]interface{}) (string, error) {
+					return e.Source, nil
+				},
+			}
+		case "gitlab_release":
+			var e hclconf.GitLabReleases
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			var baseURL string
+			if e.BaseURL != nil {
+				baseURL = *e.BaseURL
+			}
+			provider.GitLabReleases = confapi.GitLabReleases{
+				BaseURL: baseURL,
+				Source: func(_ map[string]interface{}) (string, error) {
+					return e.Source, nil
+				},
+			}
+		case "gitea_release":
+			var e hclconf.GiteaReleases
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			var token string
+			if e.Token != nil {
+				token = *e.Token
+			}
+			provider.GiteaReleases = confapi.GiteaReleases{
+				Host: e.Host,
+				Source: func(_ map[string]interface{}) (string, error) {
+					return e.Source, nil
+				},
+				Token:              token,
+				IncludePrereleases: e.IncludePrereleases != nil && *e.IncludePrereleases,
+				IncludeDrafts:      e.IncludeDrafts != nil && *e.IncludeDrafts,
 			}
 		case "docker_tag":
 			var e hclconf.DockerImageTags
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
 			var host string
 			if e.Host != nil {
 				host = *e.Host
 			}
+			var platforms []string
+			if e.Platforms != nil {
+				platforms = *e.Platforms
+			}
+			provider.DockerImageTags = confapi.DockerImageTags{
+				Host:      host,
+				Platforms: platforms,
+				Source: func(_ map[string]interface{}) (string, error) {
+					return e.Source, nil
+				},
+			}
+		case "oci_artifact_tag":
+			var e hclconf.OCIArtifactTags
 			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
 				return nil, err
 			}
-			provider.DockerImageTags = confapi.DockerImageTags{
-				Host: host,
+			var mediaType string
+			if e.MediaType != nil {
+				mediaType = *e.MediaType
+			}
+			provider.OCIArtifactTags = confapi.OCIArtifactTags{
+				Host:      e.Host,
+				MediaType: mediaType,
 				Source: func(_ map[string]interface{}) (string, error) {
 					return e.Source, nil
 				},
 			}
+		case "helm_chart":
+			var e hclconf.HelmChart
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.HelmChart = confapi.HelmChart{
+				Repository: func(_ map[string]interface{}) (string, error) {
+					return e.Repository, nil
+				},
+				Chart: e.Chart,
+			}
+		case "go_module":
+			var e hclconf.GoModule
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.GoModule = confapi.GoModule{
+				Path: func(_ map[string]interface{}) (string, error) {
+					return e.Path, nil
+				},
+				IncludeIncompatible: e.IncludeIncompatible != nil && *e.IncludeIncompatible,
+			}
+		case "npm_package":
+			var e hclconf.NpmPackage
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.NpmPackage = confapi.NpmPackage{
+				Name: func(_ map[string]interface{}) (string, error) {
+					return e.Name, nil
+				},
+			}
+			if e.Registry != nil {
+				provider.NpmPackage.Registry = *e.Registry
+			}
+			if e.DistTag != nil {
+				provider.NpmPackage.DistTag = *e.DistTag
+			}
+		case "pypi_package":
+			var e hclconf.PyPIPackage
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.PyPIPackage = confapi.PyPIPackage{
+				Name: func(_ map[string]interface{}) (string, error) {
+					return e.Name, nil
+				},
+			}
+			if e.Index != nil {
+				provider.PyPIPackage.Index = *e.Index
+			}
+		case "maven_artifact":
+			var e hclconf.MavenArtifact
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.MavenArtifact = confapi.MavenArtifact{
+				GroupID: e.GroupID,
+				ArtifactID: func(_ map[string]interface{}) (string, error) {
+					return e.ArtifactID, nil
+				},
+			}
+			if e.Repository != nil {
+				provider.MavenArtifact.Repository = *e.Repository
+			}
+			if e.ServerID != nil {
+				provider.MavenArtifact.ServerID = *e.ServerID
+			}
+		case "terraform_provider":
+			var e hclconf.TerraformProvider
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.TerraformProvider = confapi.TerraformProvider{
+				Namespace: e.Namespace,
+				Type: func(_ map[string]interface{}) (string, error) {
+					return e.Type, nil
+				},
+			}
+			if e.Host != nil {
+				provider.TerraformProvider.Host = *e.Host
+			}
+		case "terraform_module":
+			var e hclconf.TerraformModule
+			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
+				return nil, err
+			}
+			provider.TerraformModule = confapi.TerraformModule{
+				Namespace: e.Namespace,
+				Name: func(_ map[string]interface{}) (string, error) {
+					return e.Name, nil
+				},
+				System: e.System,
+			}
+			if e.Host != nil {
+				provider.TerraformModule.Host = *e.Host
+			}
 		case "json_path":
 			var e hclconf.JSONPath
 			if err := gohcl.DecodeBody(d.BodyForType, &hcl.EvalContext{}, &e); err != nil {
@@ -124,12 +307,38 @@ func HCLModuleAsConfModule(mod hclconf.Module) (*confapi.Module, error) {
 			return nil, fmt.Errorf("dependency of type %q not implemented yet", d.Type)
 		}
 
+		var minAge time.Duration
+		if d.MinimumReleaseAge != nil {
+			var err error
+			minAge, err = time.ParseDuration(*d.MinimumReleaseAge)
+			if err != nil {
+				return nil, fmt.Errorf("parsing minimum_release_age of dependency %q: %w", d.Name, err)
+			}
+		}
+
+		var updatePolicy string
+		if d.UpdatePolicy != nil {
+			updatePolicy = *d.UpdatePolicy
+		}
+
+		var ignore []confapi.IgnoreRule
+		for _, ig := range d.Ignore {
+			r := confapi.IgnoreRule{Version: ig.Version}
+			if ig.Reason != nil {
+				r.Reason = *ig.Reason
+			}
+			ignore = append(ignore, r)
+		}
+
 		rels[d.Name] = confapi.Release{VersionsFrom: provider}
 		deps[d.Name] = confapi.Dependency{
 			ReleasesFrom:      provider,
 			Source:            "",
 			Kind:              "",
 			VersionConstraint: d.Version,
+			MinimumReleaseAge: minAge,
+			UpdatePolicy:      updatePolicy,
+			Ignore:            ignore,
 			Arguments: func(v map[string]interface{}) (map[string]interface{}, error) {
 				m := map[string]interface{}{}
 				return m, nil
@@ -3,6 +3,7 @@ package variantmod
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	aliases "github.com/k-kinzal/aliases/pkg/aliases/yaml"
 	"github.com/variantdev/mod/pkg/config/confapi"
@@ -13,6 +14,15 @@ import (
 )
 
 func (m *ModuleLoader) loadYamlModule(params confapi.ModuleParams) (*confapi.Module, error) {
+	spec, err := m.readYamlModuleSpec(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return yamlModuleSpecToConf(spec)
+}
+
+func (m *ModuleLoader) readYamlModuleSpec(params confapi.ModuleParams) (*yamlconf.ModuleSpec, error) {
 	resolved, err := m.dep.ResolveFile(params.Source)
 	if err != nil {
 		return nil, err
@@ -49,6 +59,10 @@ func (m *ModuleLoader) loadYamlModule(params confapi.ModuleParams) (*confapi.Mod
 	}
 	m.Logger.V(2).Info("load", "alias", params.Alias, "module", spec, "dep", params)
 
+	return spec, nil
+}
+
+func yamlModuleSpecToConf(spec *yamlconf.ModuleSpec) (*confapi.Module, error) {
 	for n, dep := range spec.Dependencies {
 		if dep.ReleasesFrom.IsDefined() {
 			_, conflicted := spec.Releases[n]
@@ -73,11 +87,23 @@ func (m *ModuleLoader) loadYamlModule(params confapi.ModuleParams) (*confapi.Mod
 	for alias, dep := range spec.Dependencies {
 		releaseFrom := yamlconf.ToVersionsFrom(dep.ReleasesFrom)
 
+		var minAge time.Duration
+		if dep.MinimumReleaseAge != "" {
+			var err error
+			minAge, err = time.ParseDuration(dep.MinimumReleaseAge)
+			if err != nil {
+				return nil, fmt.Errorf("parsing minimumReleaseAge of dependency %q: %w", alias, err)
+			}
+		}
+
 		dependencies[alias] = confapi.Dependency{
 			ReleasesFrom:      releaseFrom,
 			Source:            dep.Source,
 			Kind:              dep.Kind,
 			VersionConstraint: dep.VersionConstraint,
+			MinimumReleaseAge: minAge,
+			UpdatePolicy:      dep.UpdatePolicy,
+			Ignore:            dep.Ignore,
 			Arguments:         yamlconf.NewRenderArgs(dep.Arguments),
 			Alias:             dep.Alias,
 			LockedVersions:    dep.LockedVersions,
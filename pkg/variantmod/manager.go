@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/variantdev/mod/pkg/deploycoordinator"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -18,9 +19,9 @@ import (
 	"github.com/variantdev/mod/pkg/depresolver"
 	"github.com/variantdev/mod/pkg/gitops"
 	"github.com/variantdev/mod/pkg/gitrepo"
+	"github.com/variantdev/mod/pkg/overlayfs"
 	"github.com/variantdev/mod/pkg/tmpl"
 	"github.com/variantdev/mod/pkg/yamlpatch"
-	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 	"k8s.io/klog"
 	"k8s.io/klog/klogr"
@@ -31,8 +32,14 @@ type ModuleManager struct {
 	LockFile   string
 	Module     *confapi.Module
 
+	// args overrides parameters.defaults of the module
+	args Values
+
 	load func(lock confapi.State) (*Module, error)
 
+	// digestNote is the note on the tags pushed again found by the last up, to be appended to the pull request body
+	digestNote string
+
 	fs   vfs.FS
 	cmdr cmdsite.RunCommand
 
@@ -44,6 +51,8 @@ type ModuleManager struct {
 	goGetterAbsWorkDir string
 	goGetterCacheDir   string
 
+	dockerRegistryHTTPClient *http.Client
+
 	dep    *depresolver.Resolver
 	loader *ModuleLoader
 }
@@ -65,7 +74,7 @@ func New(opts ...Option) (*ModuleManager, error) {
 	man.load = func(lock confapi.State) (*Module, error) {
 		spec := man.newModuleParams(confapi.ModuleParams{
 			Source:         filepath.Join(man.AbsWorkDir, man.ModuleFile),
-			Arguments:      map[string]interface{}{},
+			Arguments:      mergeByOverwrite(Values{}, man.args),
 			LockedVersions: lock,
 		})
 
@@ -234,6 +243,10 @@ func (m *ModuleManager) loadLockAndModule() (*Module, error) {
 		return nil, err
 	}
 
+	if err := validateValues(mod.ValuesSchema, mod.parameters()); err != nil {
+		return nil, err
+	}
+
 	m.Logger.V(2).Info("load.end", "mod", fmt.Sprintf("%+v", mod))
 
 	return mod, nil
@@ -241,6 +254,9 @@ func (m *ModuleManager) loadLockAndModule() (*Module, error) {
 
 type BuildResult struct {
 	Files []string
+
+	// Changes is the list of files that would have been changed by the build. Populated only for dry runs.
+	Changes []FileChange
 }
 
 func (m *ModuleManager) GetShellIfEnabled() (*cmdsite.CommandSite, error) {
@@ -262,10 +278,17 @@ func (m *ModuleManager) GetShellIfEnabled() (*cmdsite.CommandSite, error) {
 
 type BuildOpts struct {
 	Stage string
+
+	// DryRun renders all the provisioners into an in-memory overlay instead of writing files
+	DryRun bool
 }
 
 type UpOpts struct {
 	Stage string
+
+	// Only is the list of dependencies to be updated. All the dependencies are updated when empty.
+	// It can't be used with Stage, as a stage is updated to a revision of all the dependencies at once.
+	Only []string
 }
 
 func (m *ModuleManager) Build(opts ...string) (*BuildResult, error) {
@@ -282,17 +305,39 @@ func (m *ModuleManager) Build(opts ...string) (*BuildResult, error) {
 	return m.doBuild(mod, &BuildOpts{Stage: stage})
 }
 
+// Diff is the dry-run variant of Build.
+// It returns the changes that Build would make to files, without writing anything.
+func (m *ModuleManager) Diff(opts ...string) (*BuildResult, error) {
+	var stage string
+	if len(opts) > 0 {
+		stage = opts[0]
+	}
+
+	mod, err := m.loadLockAndModule()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.doBuild(mod, &BuildOpts{Stage: stage, DryRun: true})
+}
+
 func (m *ModuleManager) Up(opts ...string) error {
 	var stage string
 	if len(opts) > 0 {
 		stage = opts[0]
 	}
 
-	mod, err := m.doUp(&UpOpts{Stage: stage})
+	return m.UpWithOpts(&UpOpts{Stage: stage})
+}
+
+func (m *ModuleManager) UpWithOpts(opts *UpOpts) error {
+	mod, err := m.doUp(opts)
 	if err != nil {
 		return err
 	}
 
+	m.digestNote = mod.digestNote()
+
 	return m.lock(mod)
 }
 
@@ -366,6 +411,7 @@ func (m *ModuleManager) PullRequest(title, body, base, head string, skipDuplicat
 	if err != nil {
 		return err
 	}
+	b += mod.ignoreNote() + m.digestNote
 	t, err := tmpl.Render("title", title, mod.Values)
 	if err != nil {
 		return err
@@ -467,12 +513,17 @@ func (m *ModuleManager) doUp(opts *UpOpts) (*Module, error) {
 
 	stage := opts.Stage
 
+	if stage != "" && len(opts.Only) > 0 {
+		return nil, fmt.Errorf("--only can't be used with stage %q: a stage is updated to a revision of all the dependencies at once", stage)
+	}
+
 	spec := m.newModuleParams(confapi.ModuleParams{
 		Source:    filepath.Join(m.AbsWorkDir, m.ModuleFile),
-		Arguments: map[string]interface{}{},
+		Arguments: mergeByOverwrite(Values{}, m.args),
 		//LockedVersions: State{Dependencies: map[string]DependencyState{}},
-		LockedVersions: *lockContents,
-		ForceUpdate:    stage == "",
+		LockedVersions:  *lockContents,
+		ForceUpdate:     stage == "",
+		ForceUpdateOnly: opts.Only,
 	})
 
 	mod, err := m.loader.LoadModule(spec)
@@ -480,6 +531,11 @@ func (m *ModuleManager) doUp(opts *UpOpts) (*Module, error) {
 		return nil, err
 	}
 
+	// Arguments given via --set and --values are checked before updating anything
+	if err := validateValues(mod.ValuesSchema, mod.parameters()); err != nil {
+		return nil, err
+	}
+
 	if stage != "" {
 		d := map[string]string{}
 		for k, _ := range mod.VersionLock.Dependencies {
@@ -616,6 +672,15 @@ func mergeByOverwrite(src ...Values) (res Values) {
 
 func (m *ModuleManager) doBuild(mod *Module, opts *BuildOpts) (*BuildResult, error) {
 	r := BuildResult{}
+
+	fs := m.fs
+
+	var overlay *overlayfs.FS
+	if opts.DryRun {
+		overlay = overlayfs.New(m.fs)
+		fs = overlay
+	}
+
 	err := mod.Walk(func(dep *Module) error {
 		var stages []string
 
@@ -641,7 +706,7 @@ func (m *ModuleManager) doBuild(mod *Module, opts *BuildOpts) (*BuildResult, err
 							dep.Values[k] = v
 						}
 
-						rr, err := m.buildModule(dep)
+						rr, err := m.buildModule(fs, dep)
 						if err != nil {
 							return fmt.Errorf("building environment %q in stage %q: %w", deploy.Environment, stage.Name, err)
 						}
@@ -657,7 +722,7 @@ func (m *ModuleManager) doBuild(mod *Module, opts *BuildOpts) (*BuildResult, err
 			}
 		}
 
-		rr, err := m.buildModule(dep)
+		rr, err := m.buildModule(fs, dep)
 		if err != nil {
 			return err
 		}
@@ -669,10 +734,19 @@ func (m *ModuleManager) doBuild(mod *Module, opts *BuildOpts) (*BuildResult, err
 	if err != nil {
 		return nil, err
 	}
+
+	if overlay != nil {
+		changes, err := overlay.Changes()
+		if err != nil {
+			return nil, err
+		}
+		r.Changes = m.fileChanges(changes)
+	}
+
 	return &r, nil
 }
 
-func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
+func (m *ModuleManager) buildModule(fs vfs.FS, mod *Module) (r *BuildResult, err error) {
 	defer func() {
 		if err != nil {
 			m.Logger.V(0).Info("doBuild", "error", err.Error())
@@ -683,17 +757,14 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 		Files: []string{},
 	}
 
-	schemaLoader := gojsonschema.NewGoLoader(mod.ValuesSchema)
-	values := mergeByOverwrite(Values{}, mod.Values)
-	jsonLoader := gojsonschema.NewGoLoader(values)
-	result, err := gojsonschema.Validate(schemaLoader, jsonLoader)
-	if err != nil {
-		return nil, fmt.Errorf("validate: %v", err)
-	}
-	for i, err := range result.Errors() {
-		m.Logger.V(1).Info("err", "index", i, "err", err.String())
+	// Values of stage environments aren't validated, as they are only `stage.environment` and `stage.dependencies`
+	// derived from the stages and the locked versions, like the other values that aren't parameters
+	if err := validateValues(mod.ValuesSchema, mod.parameters()); err != nil {
+		return nil, err
 	}
 
+	values := mergeByOverwrite(Values{}, mod.Values)
+
 	for _, d := range mod.Directories {
 		src, err := d.Source(values)
 		if err != nil {
@@ -729,8 +800,8 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 		mine := filepath.Join(m.AbsWorkDir, yours)
 		m.Logger.V(1).Info("resolved", "modulefile", yours, "localfile", mine)
 
-		if _, err := m.fs.ReadDir(mine); err != nil {
-			if _, err = m.fs.ReadDir(yours); err != nil {
+		if _, err := fs.ReadDir(mine); err != nil {
+			if _, err = fs.ReadDir(yours); err != nil {
 				m.Logger.V(1).Info(err.Error())
 				return nil, err
 			}
@@ -741,7 +812,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 			dstDir = filepath.Join(m.AbsWorkDir, d.Path)
 		}
 
-		err = vfs.Walk(m.fs, srcDir, func(path string, info os.FileInfo, err error) error {
+		err = vfs.Walk(fs, srcDir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return fmt.Errorf("%s: %v", path, err)
 			}
@@ -753,7 +824,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 			for _, v := range tmpls {
 				matches := v.pat.FindStringSubmatch(path)
 
-				contents, err := m.fs.ReadFile(path)
+				contents, err := fs.ReadFile(path)
 				if err != nil {
 					return err
 				}
@@ -783,11 +854,11 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 				dstDirToWrite := filepath.Dir(dst)
 
-				if err := vfs.MkdirAll(m.fs, dstDirToWrite, 0755); err != nil {
+				if err := vfs.MkdirAll(fs, dstDirToWrite, 0755); err != nil {
 					return fmt.Errorf("mkdirall on %q: %w", dstDirToWrite, err)
 				}
 
-				if err := m.fs.WriteFile(dst, contents, info.Mode()); err != nil {
+				if err := fs.WriteFile(dst, contents, info.Mode()); err != nil {
 					m.Logger.V(1).Info(err.Error())
 					return err
 				}
@@ -820,9 +891,9 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 		mine := filepath.Join(m.AbsWorkDir, yours)
 		m.Logger.V(1).Info("resolved", "input", u, "modulefile", yours, "localfile", mine)
-		contents, err := m.fs.ReadFile(mine)
+		contents, err := fs.ReadFile(mine)
 		if err != nil {
-			contents, err = m.fs.ReadFile(yours)
+			contents, err = fs.ReadFile(yours)
 			if err != nil {
 				m.Logger.V(1).Info(err.Error())
 				return nil, err
@@ -856,11 +927,11 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 		dstDir := filepath.Dir(dstFile)
 
-		if err := vfs.MkdirAll(m.fs, dstDir, 0755); err != nil {
+		if err := vfs.MkdirAll(fs, dstDir, 0755); err != nil {
 			return nil, fmt.Errorf("mkdirall on %q: %w", dstDir, err)
 		}
 
-		if err := m.fs.WriteFile(dstFile, contents, 0644); err != nil {
+		if err := fs.WriteFile(dstFile, contents, 0644); err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
 		}
@@ -897,7 +968,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 		target := filepath.Join(m.AbsWorkDir, path)
 		m.Logger.V(1).Info("textReplace", "path", target, "from", from, "to", to)
-		contents, err := m.fs.ReadFile(target)
+		contents, err := fs.ReadFile(target)
 		if err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
@@ -905,7 +976,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 		str := strings.ReplaceAll(string(contents), from, to)
 
-		if err := m.fs.WriteFile(target, []byte(str), 0644); err != nil {
+		if err := fs.WriteFile(target, []byte(str), 0644); err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
 		}
@@ -936,7 +1007,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 
 		target := filepath.Join(m.AbsWorkDir, path)
 		m.Logger.V(1).Info("regexpReplace", "path", target, "from", from, "to", to)
-		contents, err := m.fs.ReadFile(target)
+		contents, err := fs.ReadFile(target)
 		if err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
@@ -948,7 +1019,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 			return nil, err
 		}
 
-		if err := m.fs.WriteFile(target, res, 0644); err != nil {
+		if err := fs.WriteFile(target, res, 0644); err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
 		}
@@ -963,7 +1034,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 			return nil, err
 		}
 		abspath := filepath.Join(m.AbsWorkDir, path)
-		origYAML, err := m.fs.ReadFile(abspath)
+		origYAML, err := fs.ReadFile(abspath)
 		if err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
@@ -990,7 +1061,7 @@ func (m *ModuleManager) buildModule(mod *Module) (r *BuildResult, err error) {
 			return nil, err
 		}
 
-		if err := m.fs.WriteFile(abspath, modifiedYAML, 0644); err != nil {
+		if err := fs.WriteFile(abspath, modifiedYAML, 0644); err != nil {
 			m.Logger.V(1).Info(err.Error())
 			return nil, err
 		}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"github.com/variantdev/mod/pkg/deploycoordinator"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/variantdev/mod/pkg/cmdsite"
 	"github.com/variantdev/mod/pkg/config/confapi"
 	"github.com/variantdev/mod/pkg/execversionmanager"
 	"github.com/variantdev/mod/pkg/releasetracker"
+	"github.com/variantdev/mod/pkg/versioning"
 )
 
 type Values map[string]interface{}
@@ -31,6 +34,17 @@ type Module struct {
 	Submodules      map[string]*Module
 	ReleaseTrackers map[string]*releasetracker.Tracker
 
+	// Parameters is the defaults overridden by the arguments, which is validated against ValuesSchema.
+	// Unlike Values, it doesn't include the locked versions and the values of submodules.
+	Parameters Values
+
+	// DigestChanges is the change of the digest of each dependency whose locked tag has been pushed again.
+	// It's found only by the run that updates the digest, and isn't recorded in the lock file.
+	DigestChanges map[string]DigestChange
+
+	// VersionConstraints is the version constraint of each dependency, keyed by the dependency name
+	VersionConstraints map[string]string
+
 	VersionLock confapi.State
 }
 
@@ -49,6 +63,15 @@ func (m *Module) Walk(f func(*Module) error) error {
 	return f(m)
 }
 
+// parameters returns the values to be validated against ValuesSchema.
+// Modules constructed without ModuleLoader, like the ones given via the library API, have only Values.
+func (m *Module) parameters() Values {
+	if m.Parameters == nil {
+		return m.Values
+	}
+	return m.Parameters
+}
+
 func (m *Module) getDirs() (map[string]struct{}, error) {
 	dirs := map[string]struct{}{}
 
@@ -98,13 +121,100 @@ func (m *Module) ListVersions(depName string, out io.Writer) error {
 		return err
 	}
 
+	labels := map[*releasetracker.Release]string{}
 	for _, r := range releases {
-		fmt.Fprintf(out, "%s\t%s\n", r.Version, r.Description)
+		labels[r] = r.Description
+	}
+
+	// Ignored versions are listed along with the others in the version order, so that it's clear where they would have been
+	all := append([]*releasetracker.Release{}, releases...)
+	for _, i := range dep.Ignored() {
+		labels[i.Release] = ignoredLabel(i.Reason)
+		all = append(all, i.Release)
+	}
+
+	dep.SortReleases(all)
+
+	for _, r := range all {
+		fmt.Fprintf(out, "%s\t%s\n", r.Version, labels[r])
 	}
 
 	return nil
 }
 
+// DigestChange is the change of the digest of a tag that has been pushed again with a different content
+type DigestChange struct {
+	Version  string
+	Previous string
+	Current  string
+}
+
+// digestNote returns the list of the tags pushed again, to be appended to the pull request body
+// so that reviewers can see why the lock file changed without any version change.
+func (m *Module) digestNote() string {
+	var lines []string
+
+	m.Walk(func(mod *Module) error {
+		for name, c := range mod.DigestChanges {
+			lines = append(lines, fmt.Sprintf("- `%s` %s: %s -> %s", name, c.Version, c.Previous, c.Current))
+		}
+		return nil
+	})
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	sort.Strings(lines)
+
+	return "\n\nTags pushed again:\n" + strings.Join(lines, "\n") + "\n"
+}
+
+func ignoredLabel(reason string) string {
+	if reason == "" {
+		return "(ignored)"
+	}
+	return fmt.Sprintf("(ignored: %s)", reason)
+}
+
+// ignoreNote returns the list of the versions ignored in dependencies, to be appended to the pull request body
+// so that reviewers can see why they are not adopted.
+func (m *Module) ignoreNote() string {
+	var lines []string
+
+	m.Walk(func(mod *Module) error {
+		for name, tracker := range mod.ReleaseTrackers {
+			for _, rule := range tracker.Spec.Ignore {
+				line := fmt.Sprintf("- `%s` %s", name, rule.Version)
+				if rule.Reason != "" {
+					line += ": " + rule.Reason
+				}
+				lines = append(lines, line)
+			}
+		}
+		return nil
+	})
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	sort.Strings(lines)
+
+	return "\n\nIgnored versions:\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// versioning returns the versioning scheme of each dependency
+func (m *Module) versioning() map[string]versioning.Scheme {
+	r := map[string]versioning.Scheme{}
+	for name, t := range m.ReleaseTrackers {
+		if s := t.Spec.VersionsFrom.Versioning; s != nil {
+			r[name] = s
+		}
+	}
+	return r
+}
+
 func (m *Module) Transact(f func(t *deploycoordinator.Single) error) error {
 	dc := &deploycoordinator.Single{
 		Spec: &deploycoordinator.StageSpec{
@@ -116,11 +226,13 @@ func (m *Module) Transact(f func(t *deploycoordinator.Single) error) error {
 			Stages: m.VersionLock.Stages,
 		},
 		RevisionManager: &deploycoordinator.RevisionManager{
-			Revisions: m.VersionLock.Revisions,
+			Revisions:  m.VersionLock.Revisions,
+			Versioning: m.versioning(),
 		},
 		DependencyManager: &deploycoordinator.DependencyManager{
-			State:     m.VersionLock.Dependencies,
-			StateMeta: m.VersionLock.Meta.Dependencies,
+			State:      m.VersionLock.Dependencies,
+			StateMeta:  m.VersionLock.Meta.Dependencies,
+			Versioning: m.versioning(),
 		},
 	}
 
@@ -3,6 +3,7 @@ package variantmod
 import (
 	"fmt"
 	"github.com/variantdev/mod/pkg/config/confapi"
+	"net/http"
 	"path/filepath"
 	"strings"
 
@@ -138,6 +139,20 @@ func (s *goGetterWDOption) SetOption(r *ModuleManager) error {
 	return nil
 }
 
+// DockerRegistryHTTPClient sets a custom HTTP client for Docker registry requests made by `dockerImageTags` releases providers.
+func DockerRegistryHTTPClient(c *http.Client) Option {
+	return &dockerRegistryHTTPClientOption{c: c}
+}
+
+type dockerRegistryHTTPClientOption struct {
+	c *http.Client
+}
+
+func (o *dockerRegistryHTTPClientOption) SetOption(r *ModuleManager) error {
+	r.dockerRegistryHTTPClient = o.c
+	return nil
+}
+
 type moduleOption struct {
 	mod confapi.Module
 }
@@ -150,3 +165,18 @@ func (m *moduleOption) SetOption(r *ModuleManager) error {
 	r.Module = &m.mod
 	return nil
 }
+
+// Arguments overrides `parameters.defaults` of the module.
+// Given multiple times, arguments are merged in order and the latter wins. Versions recorded in the lock file take precedence over arguments.
+func Arguments(args map[string]interface{}) Option {
+	return &argsOption{args: args}
+}
+
+type argsOption struct {
+	args map[string]interface{}
+}
+
+func (o *argsOption) SetOption(r *ModuleManager) error {
+	r.args = mergeByOverwrite(r.args, o.args)
+	return nil
+}
@@ -0,0 +1,136 @@
+package variantmod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/variantdev/mod/pkg/config/confapi"
+	"github.com/variantdev/mod/pkg/versioning"
+	"k8s.io/klog"
+)
+
+type OutdatedDependency struct {
+	Name string `json:"name"`
+
+	// Locked is the version recorded in the lock file
+	Locked string `json:"locked"`
+	// Wanted is the newest version that satisfies the version constraint of the dependency
+	Wanted string `json:"wanted"`
+	// Latest is the newest version regardless of the version constraint
+	Latest string `json:"latest"`
+
+	// Pinned is true when the locked version is pinned via `mod pin`
+	Pinned bool `json:"pinned"`
+
+	Outdated bool `json:"outdated"`
+}
+
+// Outdated compares the version of every dependency recorded in the lock file to the wanted and the latest versions
+func (m *ModuleManager) Outdated() ([]OutdatedDependency, error) {
+	lockContents, err := m.loadLockFile(m.LockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loading the module resolves and adds versions of dependencies missing in the lock file.
+	// Record the original versions beforehand so that we can report them as unlocked.
+	locked := map[string]confapi.DependencyState{}
+	for name, d := range lockContents.Dependencies {
+		locked[name] = d
+	}
+
+	mod, err := m.load(*lockContents)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.outdated(locked)
+}
+
+func (m *Module) outdated(locked map[string]confapi.DependencyState) ([]OutdatedDependency, error) {
+	var names []string
+	for name := range m.ReleaseTrackers {
+		if _, ok := m.VersionConstraints[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var r []OutdatedDependency
+
+	for _, name := range names {
+		tracker := m.ReleaseTrackers[name]
+
+		releases, err := tracker.GetReleases()
+		if err != nil {
+			return nil, fmt.Errorf("getting releases of %q: %w", name, err)
+		}
+
+		d := OutdatedDependency{
+			Name:   name,
+			Locked: locked[name].Version,
+			Pinned: locked[name].Pinned,
+		}
+
+		if wanted, err := tracker.LatestOf(m.VersionConstraints[name], releases); err != nil {
+			klog.V(1).Infof("no version of %q satisfies the constraint: %v", name, err)
+		} else {
+			d.Wanted = wanted.Version
+		}
+
+		latest, err := tracker.LatestOf("", releases)
+		if err != nil {
+			return nil, fmt.Errorf("finding latest version of %q: %w", name, err)
+		}
+		d.Latest = latest.Version
+
+		if d.Locked == "" {
+			d.Outdated = true
+		} else {
+			c, err := versioning.Or(tracker.Spec.VersionsFrom.Versioning).Compare(d.Locked, latest.Version)
+			if err != nil {
+				return nil, fmt.Errorf("parsing locked version %q of %q: %w", d.Locked, name, err)
+			}
+			d.Outdated = c < 0
+		}
+
+		r = append(r, d)
+	}
+
+	return r, nil
+}
+
+func WriteOutdatedTable(out io.Writer, deps []OutdatedDependency) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "DEPENDENCY\tLOCKED\tWANTED\tLATEST")
+
+	for _, d := range deps {
+		locked := orDash(d.Locked)
+		if d.Pinned {
+			locked += " (pinned)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Name, locked, orDash(d.Wanted), orDash(d.Latest))
+	}
+
+	return w.Flush()
+}
+
+func WriteOutdatedJSON(out io.Writer, deps []OutdatedDependency) error {
+	if deps == nil {
+		deps = []OutdatedDependency{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(deps)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package variantmod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/cmdsite"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestOutdated(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  k8s:
+    releasesFrom:
+      exec:
+        command: go
+        args:
+        - run
+        - main.go
+    version: "< 1.13"
+  helm:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - helm-versions
+    version: "> 2.0"
+  kubectl:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - kubectl-versions
+    version: "> 1.0"
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  k8s:
+    version: "1.10.13"
+  helm:
+    version: "2.14.3"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("go", []string{"run", "main.go"}, map[string]string{}):         {Stdout: "1.13.7\n1.12.6\n1.11.8\n1.10.13\n"},
+		cmdsite.NewInput("sh", []string{"-c", "helm-versions"}, map[string]string{}):    {Stdout: "2.14.2\n2.14.3\n"},
+		cmdsite.NewInput("sh", []string{"-c", "kubectl-versions"}, map[string]string{}): {Stdout: "1.15.0\n1.16.1\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps, err := man.Outdated()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []OutdatedDependency{
+		{Name: "helm", Locked: "2.14.3", Wanted: "2.14.3", Latest: "2.14.3"},
+		{Name: "k8s", Locked: "1.10.13", Wanted: "1.12.6", Latest: "1.13.7", Outdated: true},
+		{Name: "kubectl", Wanted: "1.16.1", Latest: "1.16.1", Outdated: true},
+	}
+	if d := cmp.Diff(expected, deps); d != "" {
+		t.Errorf("unexpected result: %s", d)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteOutdatedTable(buf, deps); err != nil {
+		t.Fatal(err)
+	}
+	expectedTable := `DEPENDENCY  LOCKED   WANTED  LATEST
+helm        2.14.3   2.14.3  2.14.3
+k8s         1.10.13  1.12.6  1.13.7
+kubectl     -        1.16.1  1.16.1
+`
+	if buf.String() != expectedTable {
+		t.Errorf("unexpected table: expected=\n%s\ngot=\n%s", expectedTable, buf.String())
+	}
+}
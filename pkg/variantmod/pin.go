@@ -0,0 +1,85 @@
+package variantmod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/config/confapi"
+)
+
+// Pin locks the dependency to the specific version, and marks it pinned so that `mod up` won't update it until unpinned.
+// The version must be one of the releases listed by the dependency's release tracker.
+func (m *ModuleManager) Pin(depName, version string) error {
+	mod, err := m.loadLockAndModule()
+	if err != nil {
+		return err
+	}
+
+	tracker, ok := mod.ReleaseTrackers[depName]
+	if !ok {
+		return fmt.Errorf("unable to pin %q: no release tracker found for the dependency", depName)
+	}
+
+	version = strings.TrimPrefix(version, "v")
+
+	if pat := tracker.Spec.VersionsFrom.ValidVersionPattern; pat != nil && !pat.MatchString(version) {
+		return fmt.Errorf("unable to pin %q: version %q does not match the valid version pattern %q", depName, version, pat.String())
+	}
+
+	releases, err := tracker.GetReleases()
+	if err != nil {
+		return err
+	}
+
+	var vers []string
+	for _, r := range releases {
+		if r.Version != version {
+			vers = append(vers, r.Version)
+			continue
+		}
+
+		if err := tracker.Describe(r); err != nil {
+			return err
+		}
+
+		cur := mod.VersionLock.Dependencies[depName]
+
+		prev := cur.PreviousVersion
+		if cur.Version != r.Version {
+			prev = cur.Version
+		}
+
+		mod.VersionLock.Dependencies[depName] = confapi.DependencyState{
+			Version:         r.Version,
+			PreviousVersion: prev,
+			Tag:             r.Tag,
+			Meta:            r.Meta,
+			Versions:        cur.Versions,
+			Pinned:          true,
+			FirstSeen:       firstSeenAfter(cur.FirstSeen, r.Version, tracker.Spec.VersionsFrom.Versioning),
+		}
+
+		return m.lock(mod)
+	}
+
+	return fmt.Errorf("unable to pin %q: version %q not found in %v", depName, version, vers)
+}
+
+// Unpin clears the pinned flag of the dependency, so that the next `mod up` can update it again
+func (m *ModuleManager) Unpin(depName string) error {
+	mod, err := m.loadLockAndModule()
+	if err != nil {
+		return err
+	}
+
+	cur, ok := mod.VersionLock.Dependencies[depName]
+	if !ok {
+		return fmt.Errorf("unable to unpin %q: dependency not found in the lock file", depName)
+	}
+
+	cur.Pinned = false
+
+	mod.VersionLock.Dependencies[depName] = cur
+
+	return m.lock(mod)
+}
@@ -0,0 +1,109 @@
+package variantmod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/cmdsite"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestPin(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  k8s:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - k8s-versions
+      validVersionPattern: "^1\\.1[0-3]\\."
+    version: "> 1.10"
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  k8s:
+    version: "1.12.6"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "k8s-versions"}, map[string]string{}): {Stdout: "1.14.0\n1.13.7\n1.12.6\n1.11.8\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Pin("k8s", "1.10.0"); err == nil {
+		t.Errorf("expected error for unknown version")
+	}
+
+	if err := man.Pin("k8s", "1.14.0"); err == nil {
+		t.Errorf("expected error for version not matching the valid version pattern")
+	}
+
+	if err := man.Pin("k8s", "v1.11.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pinnedLock := `dependencies:
+  k8s:
+    version: 1.11.8
+    previousVersion: 1.12.6
+    versions:
+    - 1.12.6
+    pinned: true
+`
+	assertLock := func(expected string) {
+		t.Helper()
+
+		actual, err := fs.ReadFile("/path/to/variant.lock")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != expected {
+			t.Errorf("assertion failed: expected=%s, got=%s", expected, string(actual))
+		}
+	}
+
+	assertLock(pinnedLock)
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLock(pinnedLock)
+
+	if err := man.Unpin("k8s"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLock(`dependencies:
+  k8s:
+    version: 1.13.7
+    previousVersion: 1.11.8
+    versions:
+    - 1.12.6
+    - 1.13.7
+`)
+}
@@ -0,0 +1,536 @@
+package variantmod
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/twpayne/go-vfs/vfst"
+	"github.com/variantdev/mod/pkg/cmdsite"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestUp_Only(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  k8s:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - k8s-versions
+    version: "> 1.10"
+  helm:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - helm-versions
+    version: "> 2.0"
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  k8s:
+    version: "1.10.13"
+  helm:
+    version: "2.14.2"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "k8s-versions"}, map[string]string{}):  {Stdout: "1.13.7\n1.10.13\n"},
+		cmdsite.NewInput("sh", []string{"-c", "helm-versions"}, map[string]string{}): {Stdout: "2.14.2\n2.14.3\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.UpWithOpts(&UpOpts{Only: []string{"k8s"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  helm:
+    version: 2.14.2
+    versions:
+    - 2.14.2
+  k8s:
+    version: 1.13.7
+    previousVersion: 1.10.13
+    versions:
+    - 1.13.7
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+
+	if err := man.UpWithOpts(&UpOpts{Only: []string{"kubectl"}}); err == nil {
+		t.Errorf("expected error for unknown dependency")
+	}
+
+	if err := man.UpWithOpts(&UpOpts{Stage: "prod", Only: []string{"k8s"}}); err == nil {
+		t.Errorf("expected error for a stage with --only")
+	}
+}
+
+func TestUp_MinimumReleaseAge(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  k8s:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - k8s-versions
+    version: "> 1.10"
+    minimumReleaseAge: 72h
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  k8s:
+    version: "1.10.13"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "k8s-versions"}, map[string]string{}): {Stdout: "1.13.7\n1.10.13\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1.13.7 is seen for the first time, hence too new to be adopted
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lock, err := man.loadLockFile(man.LockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k8s := lock.Dependencies["k8s"]
+	if k8s.Version != "1.10.13" {
+		t.Errorf("unexpected version: expected=1.10.13, got=%s", k8s.Version)
+	}
+	if _, ok := k8s.FirstSeen["1.13.7"]; !ok || len(k8s.FirstSeen) != 1 {
+		t.Fatalf("expected first-seen time of 1.13.7 to be recorded, got %v", k8s.FirstSeen)
+	}
+
+	if err := fs.WriteFile("/path/to/variant.lock", []byte(`
+dependencies:
+  k8s:
+    version: "1.10.13"
+    firstSeen:
+      1.13.7: 2020-01-01T00:00:00Z
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  k8s:
+    version: 1.13.7
+    previousVersion: 1.10.13
+    versions:
+    - 1.13.7
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+}
+
+func TestUp_UpdatePolicy(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  k8s:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - k8s-versions
+    version: "> 1.0"
+    updatePolicy: minor
+  helm:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - helm-versions
+    version: "> 2.0"
+    updatePolicy: patch
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  k8s:
+    version: "1.4.2"
+  helm:
+    version: "2.14.2"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "k8s-versions"}, map[string]string{}):  {Stdout: "1.4.2\n1.4.5\n1.5.0\n2.0.0\n"},
+		cmdsite.NewInput("sh", []string{"-c", "helm-versions"}, map[string]string{}): {Stdout: "2.14.2\n2.14.3\n2.15.0\n3.0.0\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  helm:
+    version: 2.14.3
+    previousVersion: 2.14.2
+    versions:
+    - 2.14.3
+  k8s:
+    version: 1.5.0
+    previousVersion: 1.4.2
+    versions:
+    - 1.5.0
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+}
+
+func TestUp_Versioning(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+dependencies:
+  python:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - python-tags
+      versioning:
+        scheme: regex
+        pattern: '^(?P<major>\d+)\.(?P<minor>\d+)-alpine(?P<alpine>[\d.]+)$'
+    version: "< 4"
+  myimage:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - myimage-tags
+      versioning: calver
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  python:
+    version: "3.12-alpine3.9"
+  myimage:
+    version: "20240315-abcdef"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "python-tags"}, map[string]string{}):  {Stdout: "3.12-alpine3.9\n3.12-alpine3.19\n3.9-alpine3.20\nlatest\n"},
+		cmdsite.NewInput("sh", []string{"-c", "myimage-tags"}, map[string]string{}): {Stdout: "20240315-abcdef\n20240401-012345\n20231231-fedcba\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  myimage:
+    version: 20240401-012345
+    previousVersion: 20240315-abcdef
+    versions:
+    - 20240401-012345
+  python:
+    version: 3.12-alpine3.19
+    previousVersion: 3.12-alpine3.9
+    versions:
+    - 3.12-alpine3.19
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+}
+
+func TestUp_VersionPattern(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+provisioners:
+  files:
+    chart.txt:
+      source: chart.txt.tpl
+      arguments:
+        version: "{{ .chart.version }}"
+        tag: "{{ .chart.tag }}"
+
+dependencies:
+  chart:
+    releasesFrom:
+      exec:
+        command: sh
+        args:
+        - -c
+        - chart-tags
+      versionPattern: '^chart/(.+)$'
+`,
+		"/path/to/chart.txt.tpl": "version={{ .version }} tag={{ .tag }}\n",
+		"/path/to/variant.lock": `
+dependencies:
+  chart:
+    version: "1.2.3"
+    tag: chart/v1.2.3
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	cmdr := cmdsite.NewTester(map[cmdsite.CommandInput]cmdsite.CommandOutput{
+		cmdsite.NewInput("sh", []string{"-c", "chart-tags"}, map[string]string{}): {Stdout: "chart/v1.2.3\nchart/v1.3.0\napp/v2.0.0\n"},
+	})
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), Commander(cmdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  chart:
+    version: 1.3.0
+    previousVersion: 1.2.3
+    tag: chart/v1.3.0
+    versions:
+    - 1.3.0
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+
+	if _, err := man.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := fs.ReadFile("/path/to/chart.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "version=1.3.0 tag=chart/v1.3.0\n"
+	if string(actual) != expected {
+		t.Errorf("assertion failed: expected=%s, got=%s", expected, string(actual))
+	}
+}
+
+func TestUp_DockerImageDigest(t *testing.T) {
+	digest := "sha256:new"
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/myorg/myimage/tags/list":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"tags":["0.1.0","0.2.0"]}`))
+		case "/v2/myorg/myimage/manifests/0.2.0":
+			w.Header().Set("Docker-Content-Digest", digest)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+name: myapp
+
+provisioners:
+  files:
+    image.txt:
+      source: image.txt.tpl
+      arguments:
+        image: "myorg/myimage:{{ .myimage.version }}@{{ .myimage.digest }}"
+
+dependencies:
+  myimage:
+    releasesFrom:
+      dockerImageTags:
+        source: myorg/myimage
+        host: ` + server.URL[len("https://"):] + `
+`,
+		"/path/to/image.txt.tpl": "{{ .image }}\n",
+		"/path/to/variant.lock": `
+dependencies:
+  myimage:
+    version: "0.2.0"
+    digest: sha256:old
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+
+	man, err := New(Logger(log), FS(fs), WD("/path/to"), GoGetterWD(filepath.Join(fs.TempDir(), "path", "to")), DockerRegistryHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0.2.0 has been pushed again with a different content
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lockActual, err := fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockExpected := `dependencies:
+  myimage:
+    version: 0.2.0
+    versions:
+    - 0.2.0
+    digest: sha256:new
+meta:
+  dependencies:
+    myimage:
+      0.2.0:
+        digest: sha256:new
+`
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+
+	// The previous digest is reported only for the run that found the change
+	expectedNote := "\n\nTags pushed again:\n- `myimage` 0.2.0: sha256:old -> sha256:new\n"
+	if man.digestNote != expectedNote {
+		t.Errorf("unexpected pull request note: expected=%q, got=%q", expectedNote, man.digestNote)
+	}
+
+	if err := man.Up(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if man.digestNote != "" {
+		t.Errorf("unexpected pull request note for unchanged digest: %q", man.digestNote)
+	}
+
+	lockActual, err = fs.ReadFile("/path/to/variant.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lockActual) != lockExpected {
+		t.Errorf("assertion failed: expected=%s, got=%s", lockExpected, string(lockActual))
+	}
+
+	if _, err := man.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := fs.ReadFile("/path/to/image.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "myorg/myimage:0.2.0@sha256:new\n"
+	if string(actual) != expected {
+		t.Errorf("assertion failed: expected=%s, got=%s", expected, string(actual))
+	}
+}
@@ -0,0 +1,54 @@
+package variantmod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/versioning"
+)
+
+// updatePolicyConstraint returns the version constraint that combines the dependency's constraint with the range
+// allowed by the update policy, relative to the locked version.
+//
+// For example, `minor` from 1.4.2 allows `>= 1.4.2, < 2.0.0`, `patch` allows `>= 1.4.2, < 1.5.0`, and `major`
+// allows `>= 1.4.2`. The constraint is returned as-is when there's no policy or no locked version yet.
+// The locked version is converted to semver with the versioning scheme, so that the range can be checked against releases.
+func updatePolicyConstraint(constraint, policy, locked string, scheme versioning.Scheme) (string, error) {
+	if policy == "" {
+		return constraint, nil
+	}
+
+	if policy != "patch" && policy != "minor" && policy != "major" {
+		return "", fmt.Errorf("unsupported update policy %q: must be one of patch, minor, or major", policy)
+	}
+
+	if locked == "" {
+		return constraint, nil
+	}
+
+	v, err := versioning.Or(scheme).Parse(locked)
+	if err != nil {
+		return "", fmt.Errorf("parsing locked version %q: %w", locked, err)
+	}
+
+	policyRange := fmt.Sprintf(">= %s", v.String())
+
+	switch policy {
+	case "patch":
+		policyRange += fmt.Sprintf(", < %d.%d.0", v.Major(), v.Minor()+1)
+	case "minor":
+		policyRange += fmt.Sprintf(", < %d.0.0", v.Major()+1)
+	}
+
+	if strings.TrimSpace(constraint) == "" {
+		return policyRange, nil
+	}
+
+	// `,` takes precedence over `||`, so the range needs to be added to every alternative
+	var alts []string
+	for _, alt := range strings.Split(constraint, "||") {
+		alts = append(alts, strings.TrimSpace(alt)+", "+policyRange)
+	}
+
+	return strings.Join(alts, " || "), nil
+}
@@ -0,0 +1,34 @@
+package variantmod
+
+import (
+	"testing"
+)
+
+func TestUpdatePolicyConstraint(t *testing.T) {
+	testcases := []struct {
+		constraint, policy, locked string
+		expected                   string
+	}{
+		{constraint: "> 1.0", policy: "", locked: "1.4.2", expected: "> 1.0"},
+		{constraint: "> 1.0", policy: "minor", locked: "", expected: "> 1.0"},
+		{constraint: "", policy: "major", locked: "1.4.2", expected: ">= 1.4.2"},
+		{constraint: "> 1.0", policy: "minor", locked: "v1.4.2", expected: "> 1.0, >= 1.4.2, < 2.0.0"},
+		{constraint: "> 1.0", policy: "patch", locked: "1.4.2", expected: "> 1.0, >= 1.4.2, < 1.5.0"},
+		{constraint: "1.x || 2.x", policy: "patch", locked: "1.4.2", expected: "1.x, >= 1.4.2, < 1.5.0 || 2.x, >= 1.4.2, < 1.5.0"},
+	}
+
+	for i, tc := range testcases {
+		actual, err := updatePolicyConstraint(tc.constraint, tc.policy, tc.locked, nil)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+
+		if actual != tc.expected {
+			t.Errorf("#%d: expected %q, got %q", i, tc.expected, actual)
+		}
+	}
+
+	if _, err := updatePolicyConstraint("> 1.0", "latest", "1.4.2", nil); err == nil {
+		t.Error("expected error for unsupported policy")
+	}
+}
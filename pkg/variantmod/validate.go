@@ -0,0 +1,163 @@
+package variantmod
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/config/confapi"
+	"github.com/variantdev/mod/pkg/dockerregistry"
+	"github.com/variantdev/mod/pkg/releasetracker"
+	"github.com/variantdev/mod/pkg/versioning"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidationError is returned when the module or the values given to the module are invalid.
+// Every problem found is reported on its own line.
+type ValidationError struct {
+	Summary  string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Summary + ":\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// validateValues validates the values against the JSON schema given as `parameters.schema`.
+// Each violation is reported along with the JSON pointer to the offending value, the expectation, and the actual value.
+func validateValues(schema, values Values) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewGoLoader(values))
+	if err != nil {
+		return fmt.Errorf("validate: %v", err)
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	var problems []string
+	for _, e := range result.Errors() {
+		problems = append(problems, formatSchemaViolation(e))
+	}
+	sort.Strings(problems)
+
+	return &ValidationError{
+		Summary:  "values do not conform to parameters.schema",
+		Problems: problems,
+	}
+}
+
+func formatSchemaViolation(e gojsonschema.ResultError) string {
+	pointer := strings.TrimPrefix(e.Context().String("/"), gojsonschema.STRING_CONTEXT_ROOT)
+	if pointer == "" {
+		pointer = "/"
+	}
+
+	msg := fmt.Sprintf("%s: %s", pointer, e.Description())
+
+	// Omit the whole object as it's too verbose to be included, like when a required property is missing
+	if _, isObject := e.Value().(map[string]interface{}); !isObject {
+		actual, err := json.Marshal(e.Value())
+		if err != nil {
+			actual = []byte(fmt.Sprintf("%v", e.Value()))
+		}
+		msg += fmt.Sprintf(" (actual value: %s)", actual)
+	}
+
+	return msg
+}
+
+// Validate checks the module without fetching any dependency, release, or remote file.
+// It reports invalid values according to `parameters.schema`, syntax errors in templates, and invalid release providers.
+func (m *ModuleManager) Validate() error {
+	lock, err := m.loadLockFile(m.LockFile)
+	if err != nil {
+		return err
+	}
+
+	params := m.newModuleParams(confapi.ModuleParams{
+		Source:         filepath.Join(m.AbsWorkDir, m.ModuleFile),
+		Arguments:      mergeByOverwrite(Values{}, m.args),
+		LockedVersions: *lock,
+	})
+
+	var problems []string
+
+	conf := params.Module
+	if conf == nil && !strings.HasSuffix(params.Source, ".variantmod") {
+		spec, err := m.loader.readYamlModuleSpec(params)
+		if err != nil {
+			return err
+		}
+
+		for _, err := range spec.Validate() {
+			problems = append(problems, err.Error())
+		}
+
+		conf, err = yamlModuleSpecToConf(spec)
+		if err != nil {
+			return err
+		}
+	} else {
+		if conf == nil {
+			conf, err = m.loader.loadHclModule(params)
+			if err != nil {
+				return err
+			}
+		}
+
+		// HCL modules are already checked while being parsed, except for the patterns and the platforms
+		var aliases []string
+		for alias := range conf.Releases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+
+		for _, alias := range aliases {
+			pat := conf.Releases[alias].VersionsFrom.ValidVersionPattern
+			if _, err := regexp.Compile(pat); err != nil {
+				problems = append(problems, fmt.Sprintf("releases[%q].validVersionPattern: %v", alias, err))
+			}
+			if pat := conf.Releases[alias].VersionsFrom.VersionPattern; pat != "" {
+				if _, err := releasetracker.CompileVersionPattern(pat); err != nil {
+					problems = append(problems, fmt.Sprintf("releases[%q].versionPattern: %v", alias, err))
+				}
+			}
+			if _, err := versioning.New(conf.Releases[alias].VersionsFrom.Versioning); err != nil {
+				problems = append(problems, fmt.Sprintf("releases[%q].versioning: %v", alias, err))
+			}
+			for i, p := range conf.Releases[alias].VersionsFrom.DockerImageTags.Platforms {
+				if _, err := dockerregistry.ParsePlatform(p); err != nil {
+					problems = append(problems, fmt.Sprintf("releases[%q].dockerImageTags.platforms[%d]: %v", alias, i, err))
+				}
+			}
+		}
+	}
+
+	// The locked versions aren't parameters, so they aren't subject to the schema
+	values := mergeByOverwrite(Values{}, conf.Defaults, params.Arguments)
+	if err := validateValues(conf.ValuesSchema, values); err != nil {
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			return err
+		}
+		problems = append(problems, verr.Problems...)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{
+			Summary:  fmt.Sprintf("%d problem(s) found in %s", len(problems), m.ModuleFile),
+			Problems: problems,
+		}
+	}
+
+	return nil
+}
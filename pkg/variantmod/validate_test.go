@@ -0,0 +1,311 @@
+package variantmod
+
+import (
+	"os"
+	"testing"
+
+	"github.com/twpayne/go-vfs/vfst"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+func TestBuild_SchemaViolation(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    properties:
+      replicas:
+        type: integer
+  defaults:
+    replicas: three
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        replicas: "{{.replicas}}"
+`,
+		"/path/to/src.yaml.tpl": "replicas: {{.replicas}}\n",
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = man.Build()
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	expected := `values do not conform to parameters.schema:
+  /replicas: Invalid type. Expected: integer, given: string (actual value: "three")`
+	if err.Error() != expected {
+		t.Errorf("unexpected error: expected=%q, got=%q", expected, err.Error())
+	}
+
+	if _, err := fs.ReadFile("/path/to/dst.yaml"); err == nil {
+		t.Error("dst.yaml should not be written when the values are invalid")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    properties:
+      replicas:
+        type: integer
+    required:
+    - namespace
+  defaults:
+    replicas: three
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        replicas: "{{.replicas"
+  textReplace:
+    Dockerfile:
+      from: "FROM alpine:3.9"
+      to: "FROM alpine:{{ .alpine.version }}"
+
+dependencies:
+  alpine:
+    releasesFrom:
+      dockerImageTags:
+        source: library/alpine
+      githubReleases:
+        source: alpinelinux/aports
+      validVersionPattern: "^3\\.(\\d+$"
+    version: "> 3.9"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = man.Validate()
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	expected := `5 problem(s) found in variant.mod:
+  dependencies["alpine"].releasesFrom.validVersionPattern: error parsing regexp: missing closing ): ` + "`^3\\.(\\d+$`" + `
+  dependencies["alpine"].releasesFrom: only one release provider can be defined, but got [githubReleases dockerImageTags]
+  provisioners.files["dst.yaml"].arguments: template: replicas: "{{.replicas":1: unclosed action
+  /: namespace is required
+  /replicas: Invalid type. Expected: integer, given: string (actual value: "three")`
+	if err.Error() != expected {
+		t.Errorf("unexpected error:\nexpected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    properties:
+      replicas:
+        type: integer
+  defaults:
+    replicas: 3
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        replicas: "{{.replicas}}"
+
+dependencies:
+  alpine:
+    releasesFrom:
+      dockerImageTags:
+        source: library/alpine
+    version: "> 3.9"
+`,
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := man.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_AdditionalPropertiesWithLockedVersions(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    type: object
+    properties:
+      replicas:
+        type: integer
+    additionalProperties: false
+  defaults:
+    replicas: 3
+
+provisioners:
+  files:
+    dst.yaml:
+      source: src.yaml.tpl
+      arguments:
+        replicas: "{{.replicas}}"
+        alpine: "{{.alpine.version}}"
+
+dependencies:
+  alpine:
+    releasesFrom:
+      dockerImageTags:
+        source: library/alpine
+    version: "> 3.9"
+`,
+		"/path/to/variant.lock": `
+dependencies:
+  alpine:
+    version: "3.10.0"
+`,
+		"/path/to/src.yaml.tpl": "replicas: {{.replicas}}\nalpine: {{.alpine}}\n",
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The locked versions and the internal values like `Dependencies` aren't parameters, so they must not violate the schema
+	if err := man.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := man.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := fs.ReadFile("/path/to/dst.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "replicas: 3\nalpine: 3.10.0\n"
+	if string(actual) != expected {
+		t.Errorf("unexpected dst.yaml: expected=%q, got=%q", expected, string(actual))
+	}
+}
+
+func TestBuild_StageWithAdditionalPropertiesFalse(t *testing.T) {
+	files := map[string]interface{}{
+		"/path/to/variant.mod": `
+parameters:
+  schema:
+    type: object
+    properties:
+      replicas:
+        type: integer
+    additionalProperties: false
+  defaults:
+    replicas: 3
+
+stages:
+- name: dev
+  environments:
+  - dev1
+
+provisioners:
+  files:
+    dst.yaml:
+      path: environments/{{ .stage.environment }}/dst.yaml
+      source: src.yaml.tpl
+      arguments:
+        replicas: "{{.replicas}}"
+        myapp: "{{ .stage.dependencies.myapp.version }}"
+
+dependencies:
+  myapp:
+    releasesFrom:
+      exec:
+        command: go
+        args:
+        - run
+        - main.go
+    version: "> 1.10"
+`,
+		"/path/to/variant.lock": `
+revisions:
+- id: 1
+  versions:
+    myapp: 1.10.13
+stages:
+- name: dev
+  revision: 1
+dependencies:
+  myapp:
+    versions:
+    - "1.10.13"
+`,
+		"/path/to/src.yaml.tpl": "replicas: {{.replicas}}\nmyapp: {{.myapp}}\n",
+	}
+	fs, clean, err := vfst.NewTestFS(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clean()
+	log := klogr.New()
+	klog.SetOutput(os.Stderr)
+	man, err := New(Logger(log), FS(fs), WD("/path/to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The `stage` values of the environments aren't parameters, so they must not violate the schema
+	if _, err := man.Build("dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := fs.ReadFile("/path/to/environments/dev1/dst.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "replicas: 3\nmyapp: 1.10.13\n"
+	if string(actual) != expected {
+		t.Errorf("unexpected dst.yaml: expected=%q, got=%q", expected, string(actual))
+	}
+}
@@ -0,0 +1,314 @@
+package versioning
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/semver"
+)
+
+// Maven parses versions of Maven artifacts and orders them as Maven does, like `1.0-alpha-1` < `1.0-rc1` < `1.0` < `1.0-sp1`.
+// For checking version constraints, the first three numbers are used, and the rest of the version becomes a semver
+// pre-release only when Maven orders it before the release, like `2.0.0-rc.1` for `2.0.0-RC1`.
+var Maven Scheme = mavenScheme{}
+
+type mavenScheme struct{}
+
+func (mavenScheme) Parse(v string) (*semver.Version, error) {
+	items := parseMavenVersion(v)
+
+	nums := []string{"0", "0", "0"}
+
+	var i int
+	for ; i < len(items) && i < len(nums); i++ {
+		n, ok := items[i].(mavenInt)
+		if !ok {
+			break
+		}
+		nums[i] = string(n)
+	}
+
+	// Numbers after the third one are only used for ordering
+	for i < len(items) {
+		if _, ok := items[i].(mavenInt); !ok {
+			break
+		}
+		i++
+	}
+
+	s := strings.Join(nums, ".")
+
+	if rest := items[i:]; compareMavenItem(rest, nil) < 0 {
+		s += "-" + strings.Join(rest.strings(), ".")
+	}
+
+	return semver.Parse(s)
+}
+
+func (mavenScheme) Compare(a, b string) (int, error) {
+	return compareMavenItem(parseMavenVersion(a), parseMavenVersion(b)), nil
+}
+
+// mavenItem is one of mavenInt, mavenString, and mavenList, which are the items of versions in ComparableVersion of Maven
+type mavenItem interface{}
+
+// mavenInt is a number without leading zeros
+type mavenInt string
+
+// mavenString is a qualifier like `alpha` or `sp`
+type mavenString string
+
+// mavenList is the items after a hyphen or a transition between digits and letters
+type mavenList []mavenItem
+
+// mavenQualifiers is the well-known qualifiers in the order of precedence, where the empty string is the release.
+// Unknown qualifiers come after them in lexical order.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+func newMavenString(s string, followedByDigit bool) mavenString {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+
+	if alias, ok := mavenQualifierAliases[s]; ok {
+		s = alias
+	}
+
+	return mavenString(s)
+}
+
+// comparable returns the key to compare qualifiers lexically
+func (s mavenString) comparable() string {
+	for i, q := range mavenQualifiers {
+		if string(s) == q {
+			return strconv.Itoa(i)
+		}
+	}
+
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + string(s)
+}
+
+var mavenReleaseIndex = mavenString("").comparable()
+
+func newMavenInt(s string) mavenInt {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		s = "0"
+	}
+	return mavenInt(s)
+}
+
+func parseMavenItem(isDigit bool, s string) mavenItem {
+	if isDigit {
+		return newMavenInt(s)
+	}
+	return newMavenString(s, false)
+}
+
+// parseMavenVersion splits the version into items by dots, hyphens, and transitions between digits and letters,
+// where hyphens and transitions start sub-lists, as ComparableVersion of Maven does
+func parseMavenVersion(v string) mavenList {
+	v = strings.ToLower(strings.TrimSpace(v))
+
+	root := &mavenList{}
+	list := root
+
+	startSubList := func() {
+		sub := &mavenList{}
+		*list = append(*list, sub)
+		list = sub
+	}
+
+	var isDigit bool
+
+	start := 0
+
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+
+		switch {
+		case c == '.':
+			if i == start {
+				*list = append(*list, mavenInt("0"))
+			} else {
+				*list = append(*list, parseMavenItem(isDigit, v[start:i]))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				*list = append(*list, mavenInt("0"))
+			} else {
+				*list = append(*list, parseMavenItem(isDigit, v[start:i]))
+			}
+			start = i + 1
+			startSubList()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				*list = append(*list, newMavenString(v[start:i], true))
+				start = i
+				startSubList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				*list = append(*list, parseMavenItem(true, v[start:i]))
+				start = i
+				startSubList()
+			}
+			isDigit = false
+		}
+	}
+
+	if len(v) > start {
+		*list = append(*list, parseMavenItem(isDigit, v[start:]))
+	}
+
+	return resolveMavenList(*root)
+}
+
+// resolveMavenList replaces pointers to sub-lists with the normalized sub-lists
+func resolveMavenList(l mavenList) mavenList {
+	r := mavenList{}
+
+	for _, item := range l {
+		if sub, ok := item.(*mavenList); ok {
+			item = resolveMavenList(*sub)
+		}
+		r = append(r, item)
+	}
+
+	return r.normalize()
+}
+
+// normalize removes trailing items that are equivalent to nothing, like `0` and `final`,
+// so that `1.0.0` is the same as `1` and `1-final`
+func (l mavenList) normalize() mavenList {
+	for i := len(l) - 1; i >= 0; i-- {
+		if isNullMavenItem(l[i]) {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, ok := l[i].(mavenList); !ok {
+			break
+		}
+	}
+
+	return l
+}
+
+func isNullMavenItem(item mavenItem) bool {
+	switch typed := item.(type) {
+	case mavenInt:
+		return typed == "0"
+	case mavenString:
+		return typed.comparable() == mavenReleaseIndex
+	case mavenList:
+		return len(typed) == 0
+	}
+
+	return false
+}
+
+func (l mavenList) strings() []string {
+	var r []string
+
+	for _, item := range l {
+		switch typed := item.(type) {
+		case mavenInt:
+			r = append(r, string(typed))
+		case mavenString:
+			r = append(r, string(typed))
+		case mavenList:
+			r = append(r, typed.strings()...)
+		}
+	}
+
+	return r
+}
+
+// compareMavenItem compares the items, where nil means the absence of an item
+func compareMavenItem(a, b mavenItem) int {
+	if a == nil {
+		if b == nil {
+			return 0
+		}
+		return -compareMavenItem(b, nil)
+	}
+
+	switch ta := a.(type) {
+	case mavenInt:
+		switch tb := b.(type) {
+		case nil:
+			if ta == "0" {
+				return 0
+			}
+			return 1
+		case mavenInt:
+			return compareNumbers(string(ta), string(tb))
+		default:
+			// Numbers come after qualifiers and sub-lists, like `1.1` > `1-sp` and `1.1` > `1-1`
+			return 1
+		}
+	case mavenString:
+		switch tb := b.(type) {
+		case nil:
+			return strings.Compare(ta.comparable(), mavenReleaseIndex)
+		case mavenString:
+			return strings.Compare(ta.comparable(), tb.comparable())
+		default:
+			return -1
+		}
+	case mavenList:
+		switch tb := b.(type) {
+		case nil:
+			if len(ta) == 0 {
+				return 0
+			}
+			return compareMavenItem(ta[0], nil)
+		case mavenInt:
+			return -1
+		case mavenString:
+			return 1
+		case mavenList:
+			for i := 0; i < len(ta) || i < len(tb); i++ {
+				var l, r mavenItem
+				if i < len(ta) {
+					l = ta[i]
+				}
+				if i < len(tb) {
+					r = tb[i]
+				}
+				if c := compareMavenItem(l, r); c != 0 {
+					return c
+				}
+			}
+			return 0
+		}
+	}
+
+	return 0
+}
+
+// compareNumbers compares numbers without leading zeros, which can be larger than uint64
+func compareNumbers(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,314 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/semver"
+)
+
+// PEP440 parses versions of Python packages as specified in PEP 440, like `2.0.0rc1`, `1.0.post1` and `1!2.0.dev3`.
+// For checking version constraints, pre-releases and dev releases are handled as semver pre-releases like `2.0.0-rc.1`,
+// while epochs, post releases, local versions, and the release numbers after the third are only used for ordering.
+var PEP440 Scheme = pep440Scheme{}
+
+// NormalizePEP440 returns the canonical form of the PEP 440 version, so that `1.0-RC.1` and `1.0rc1` are the same.
+func NormalizePEP440(v string) (string, error) {
+	pv, err := parsePEP440(v)
+	if err != nil {
+		return "", err
+	}
+
+	return pv.String(), nil
+}
+
+type pep440Scheme struct{}
+
+func (pep440Scheme) Parse(v string) (*semver.Version, error) {
+	pv, err := parsePEP440(v)
+	if err != nil {
+		return nil, err
+	}
+
+	nums := make([]string, 3)
+	for i := range nums {
+		nums[i] = "0"
+		if i < len(pv.release) {
+			nums[i] = strconv.FormatUint(pv.release[i], 10)
+		}
+	}
+
+	var pre []string
+	if pv.pre != "" {
+		pre = append(pre, pv.pre, strconv.FormatUint(pv.preN, 10))
+	}
+	if pv.dev >= 0 {
+		pre = append(pre, "dev", strconv.FormatInt(pv.dev, 10))
+	}
+
+	s := strings.Join(nums, ".")
+	if len(pre) > 0 {
+		s += "-" + strings.Join(pre, ".")
+	}
+
+	return semver.Parse(s)
+}
+
+func (pep440Scheme) Compare(a, b string) (int, error) {
+	va, err := parsePEP440(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := parsePEP440(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return va.compare(vb), nil
+}
+
+var pep440Pattern = regexp.MustCompile(`(?i)^v?` +
+	`(?:(?P<epoch>\d+)!)?` +
+	`(?P<release>\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(?P<pre>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<preN>\d+)?)?` +
+	`(?:-(?P<postN1>\d+)|[-_.]?(?P<post>post|rev|r)[-_.]?(?P<postN2>\d+)?)?` +
+	`(?:[-_.]?(?P<dev>dev)[-_.]?(?P<devN>\d+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+type pep440Version struct {
+	epoch   uint64
+	release []uint64
+
+	// pre is one of `a`, `b`, and `rc`, or empty for versions that aren't pre-releases
+	pre  string
+	preN uint64
+
+	// post and dev are -1 for versions that aren't post or dev releases
+	post int64
+	dev  int64
+
+	local []string
+}
+
+func parsePEP440(v string) (*pep440Version, error) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a PEP 440 version", v)
+	}
+
+	g := map[string]string{}
+	for i, n := range pep440Pattern.SubexpNames() {
+		if n != "" {
+			g[n] = strings.ToLower(m[i])
+		}
+	}
+
+	num := func(s string) (uint64, error) {
+		n, err := strconv.ParseUint(orZero(s), 10, 63)
+		if err != nil {
+			return 0, fmt.Errorf("parsing %q: %w", v, err)
+		}
+		return n, nil
+	}
+
+	pv := &pep440Version{post: -1, dev: -1}
+
+	var err error
+
+	if pv.epoch, err = num(g["epoch"]); err != nil {
+		return nil, err
+	}
+
+	for _, r := range strings.Split(g["release"], ".") {
+		n, err := num(r)
+		if err != nil {
+			return nil, err
+		}
+		pv.release = append(pv.release, n)
+	}
+
+	switch g["pre"] {
+	case "":
+	case "a", "alpha":
+		pv.pre = "a"
+	case "b", "beta":
+		pv.pre = "b"
+	default:
+		pv.pre = "rc"
+	}
+	if pv.pre != "" {
+		if pv.preN, err = num(g["preN"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if g["postN1"] != "" || g["post"] != "" {
+		n, err := num(g["postN1"] + g["postN2"])
+		if err != nil {
+			return nil, err
+		}
+		pv.post = int64(n)
+	}
+
+	if g["dev"] != "" {
+		n, err := num(g["devN"])
+		if err != nil {
+			return nil, err
+		}
+		pv.dev = int64(n)
+	}
+
+	if g["local"] != "" {
+		pv.local = strings.FieldsFunc(g["local"], func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	}
+
+	return pv, nil
+}
+
+func (v *pep440Version) String() string {
+	var b strings.Builder
+
+	if v.epoch != 0 {
+		fmt.Fprintf(&b, "%d!", v.epoch)
+	}
+
+	for i, r := range v.release {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(strconv.FormatUint(r, 10))
+	}
+
+	if v.pre != "" {
+		fmt.Fprintf(&b, "%s%d", v.pre, v.preN)
+	}
+
+	if v.post >= 0 {
+		fmt.Fprintf(&b, ".post%d", v.post)
+	}
+
+	if v.dev >= 0 {
+		fmt.Fprintf(&b, ".dev%d", v.dev)
+	}
+
+	if len(v.local) > 0 {
+		b.WriteString("+" + strings.Join(v.local, "."))
+	}
+
+	return b.String()
+}
+
+// compare orders versions as PEP 440 does:
+// dev releases come before pre-releases, which come before the final release, which comes before post releases.
+func (v *pep440Version) compare(o *pep440Version) int {
+	if c := compareUint(v.epoch, o.epoch); c != 0 {
+		return c
+	}
+
+	// Trailing zeros are insignificant, so that `1.0` and `1.0.0` are the same
+	for i := 0; i < len(v.release) || i < len(o.release); i++ {
+		var a, b uint64
+		if i < len(v.release) {
+			a = v.release[i]
+		}
+		if i < len(o.release) {
+			b = o.release[i]
+		}
+		if c := compareUint(a, b); c != 0 {
+			return c
+		}
+	}
+
+	if c := compareInt(v.preKey(), o.preKey()); c != 0 {
+		return c
+	}
+
+	if c := compareUint(v.preN, o.preN); c != 0 {
+		return c
+	}
+
+	if c := compareInt(v.post, o.post); c != 0 {
+		return c
+	}
+
+	// Versions that aren't dev releases come after dev releases
+	da, db := v.dev, o.dev
+	if da < 0 {
+		da = 1<<63 - 1
+	}
+	if db < 0 {
+		db = 1<<63 - 1
+	}
+	if c := compareInt(da, db); c != 0 {
+		return c
+	}
+
+	return compareLocal(v.local, o.local)
+}
+
+// preKey ranks the pre-release phase, where dev releases of final releases like `1.0.dev1` come before any pre-releases
+func (v *pep440Version) preKey() int64 {
+	switch v.pre {
+	case "a":
+		return 1
+	case "b":
+		return 2
+	case "rc":
+		return 3
+	}
+
+	if v.post < 0 && v.dev >= 0 {
+		return 0
+	}
+
+	return 4
+}
+
+// compareLocal compares local version labels segment by segment, where numeric segments come after alphanumeric ones
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.ParseUint(a[i], 10, 64)
+		nb, errB := strconv.ParseUint(b[i], 10, 64)
+
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareUint(na, nb)
+		case errA == nil:
+			c = 1
+		case errB == nil:
+			c = -1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(int64(len(a)), int64(len(b)))
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,316 @@
+// Package versioning provides the schemes to parse and order version numbers that aren't necessarily semver.
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/variantdev/mod/pkg/semver"
+)
+
+// Scheme parses and compares version numbers.
+type Scheme interface {
+	// Parse returns the semver representation of the version, which is used for checking version constraints.
+	// It returns an error when the version doesn't follow the scheme.
+	Parse(v string) (*semver.Version, error)
+
+	// Compare returns -1, 0, or 1 when a is older than, the same as, or newer than b respectively.
+	Compare(a, b string) (int, error)
+}
+
+// Spec is the user-provided configuration of a versioning scheme.
+type Spec struct {
+	// Scheme is one of `semver`, `calver`, `loose`, `pep440`, `maven`, and `regex`. Defaults to `semver`.
+	Scheme string `yaml:"scheme"`
+
+	// Pattern is the regular expression with named capture groups for the `regex` scheme
+	Pattern string `yaml:"pattern"`
+
+	// SortBy is the names of the capture groups to compare versions by, in the order of precedence.
+	// Defaults to all the named groups in the pattern.
+	SortBy []string `yaml:"sortBy"`
+}
+
+// Semver is the default scheme that parses versions as semver.
+// Versions with four or more numbers like `1.2.3.4` are handled as `1.2.3-4`.
+var Semver Scheme = semverScheme{}
+
+// Calver parses date-based versions like `2024.03.15`, `2024.03` and `20240315-abcdef`.
+// Anything after the date is compared naturally only when the dates are the same.
+var Calver Scheme = keyScheme(parseCalver)
+
+// Loose parses the first run of up to three dot-separated numbers out of the version, as in `3.12-alpine3.19`.
+// Anything after the numbers is compared naturally only when the numbers are the same.
+var Loose Scheme = keyScheme(parseLoose)
+
+// New returns the scheme for the spec.
+func New(spec Spec) (Scheme, error) {
+	switch spec.Scheme {
+	case "", "semver":
+		return Semver, nil
+	case "calver":
+		return Calver, nil
+	case "loose":
+		return Loose, nil
+	case "pep440":
+		return PEP440, nil
+	case "maven":
+		return Maven, nil
+	case "regex":
+		return NewRegex(spec.Pattern, spec.SortBy)
+	}
+
+	return nil, fmt.Errorf("unsupported versioning scheme %q: must be one of semver, calver, loose, pep440, maven, or regex", spec.Scheme)
+}
+
+// Or returns the scheme, or Semver when it is nil.
+func Or(s Scheme) Scheme {
+	if s == nil {
+		return Semver
+	}
+	return s
+}
+
+type semverScheme struct{}
+
+func (semverScheme) Parse(v string) (*semver.Version, error) {
+	return semver.Parse(v)
+}
+
+func (s semverScheme) Compare(a, b string) (int, error) {
+	va, err := s.Parse(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := s.Parse(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return va.Compare(vb), nil
+}
+
+// keyScheme parses a version into the semver key for ordering, and the rest of the version for breaking ties.
+type keyScheme func(v string) (*semver.Version, string, error)
+
+func (f keyScheme) Parse(v string) (*semver.Version, error) {
+	key, _, err := f(v)
+	return key, err
+}
+
+func (f keyScheme) Compare(a, b string) (int, error) {
+	ka, ra, err := f(a)
+	if err != nil {
+		return 0, err
+	}
+
+	kb, rb, err := f(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if c := ka.Compare(kb); c != 0 {
+		return c, nil
+	}
+
+	return compareNaturally(ra, rb), nil
+}
+
+var calverPattern = regexp.MustCompile(`^v?(\d{4})[.-]?(\d{1,2})(?:[.-]?(\d{1,2}))?(?:[-+_.](.+))?$`)
+
+func parseCalver(v string) (*semver.Version, string, error) {
+	m := calverPattern.FindStringSubmatch(v)
+	if m == nil {
+		return nil, "", fmt.Errorf("%q is not a calendar version", v)
+	}
+
+	month, _ := strconv.Atoi(m[2])
+	day := 0
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+
+	if month < 1 || month > 12 || day > 31 {
+		return nil, "", fmt.Errorf("%q is not a calendar version: invalid date", v)
+	}
+
+	key, err := semver.Parse(fmt.Sprintf("%s.%d.%d", m[1], month, day))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, m[4], nil
+}
+
+var loosePattern = regexp.MustCompile(`^\D*?(\d+)(?:\.(\d+))?(?:\.(\d+))?(.*)$`)
+
+func parseLoose(v string) (*semver.Version, string, error) {
+	m := loosePattern.FindStringSubmatch(v)
+	if m == nil {
+		return nil, "", fmt.Errorf("%q contains no number", v)
+	}
+
+	nums := []string{m[1], m[2], m[3]}
+	for i := range nums {
+		n, err := strconv.ParseUint(orZero(nums[i]), 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing %q: %w", v, err)
+		}
+		nums[i] = strconv.FormatUint(n, 10)
+	}
+
+	key, err := semver.Parse(strings.Join(nums, "."))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, m[4], nil
+}
+
+type regexScheme struct {
+	pattern *regexp.Regexp
+	sortBy  []string
+}
+
+// NewRegex returns the scheme that extracts parts of versions with the named capture groups in the pattern,
+// and compares versions by the parts named in sortBy, in the order of precedence.
+// The groups named `major`, `minor`, and `patch` are used for checking version constraints.
+func NewRegex(pattern string, sortBy []string) (Scheme, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling versioning pattern %q: %w", pattern, err)
+	}
+
+	groups := map[string]bool{}
+
+	var names []string
+
+	for _, n := range re.SubexpNames() {
+		if n != "" {
+			groups[n] = true
+			names = append(names, n)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("versioning pattern %q has no named capture groups", pattern)
+	}
+
+	for _, n := range sortBy {
+		if !groups[n] {
+			return nil, fmt.Errorf("versioning pattern %q has no capture group named %q", pattern, n)
+		}
+	}
+
+	if len(sortBy) == 0 {
+		sortBy = names
+	}
+
+	return &regexScheme{pattern: re, sortBy: sortBy}, nil
+}
+
+func (s *regexScheme) groups(v string) (map[string]string, error) {
+	m := s.pattern.FindStringSubmatch(v)
+	if m == nil {
+		return nil, fmt.Errorf("%q does not match %q", v, s.pattern.String())
+	}
+
+	r := map[string]string{}
+	for i, n := range s.pattern.SubexpNames() {
+		if n != "" {
+			r[n] = m[i]
+		}
+	}
+
+	return r, nil
+}
+
+func (s *regexScheme) Parse(v string) (*semver.Version, error) {
+	g, err := s.groups(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var nums []string
+	for _, n := range []string{"major", "minor", "patch"} {
+		i, err := strconv.ParseUint(orZero(g[n]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s of %q: %w", n, v, err)
+		}
+		nums = append(nums, strconv.FormatUint(i, 10))
+	}
+
+	return semver.Parse(strings.Join(nums, "."))
+}
+
+func (s *regexScheme) Compare(a, b string) (int, error) {
+	ga, err := s.groups(a)
+	if err != nil {
+		return 0, err
+	}
+
+	gb, err := s.groups(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range s.sortBy {
+		if c := compareNaturally(ga[n], gb[n]); c != 0 {
+			return c, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+var chunkPattern = regexp.MustCompile(`\d+|\D+`)
+
+// compareNaturally compares strings chunk by chunk, where runs of digits are compared numerically,
+// so that `alpine3.9` is older than `alpine3.19`.
+func compareNaturally(a, b string) int {
+	ca := chunkPattern.FindAllString(a, -1)
+	cb := chunkPattern.FindAllString(b, -1)
+
+	for i := 0; i < len(ca) && i < len(cb); i++ {
+		if c := compareChunks(ca[i], cb[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(ca) < len(cb):
+		return -1
+	case len(ca) > len(cb):
+		return 1
+	}
+
+	return 0
+}
+
+func compareChunks(a, b string) int {
+	if isDigits(a) && isDigits(b) {
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+func isDigits(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
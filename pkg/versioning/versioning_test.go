@@ -0,0 +1,164 @@
+package versioning
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchemes_Order(t *testing.T) {
+	testcases := []struct {
+		spec     Spec
+		versions []string
+		expected []string
+	}{
+		{
+			spec:     Spec{},
+			versions: []string{"1.10.0", "v1.9.1", "1.9.1.2"},
+			expected: []string{"1.9.1.2", "v1.9.1", "1.10.0"},
+		},
+		{
+			spec:     Spec{Scheme: "calver"},
+			versions: []string{"2024.03.15", "2023.12.01", "2024.3.2", "2024.03.15.2", "2024.03.15.10"},
+			expected: []string{"2023.12.01", "2024.3.2", "2024.03.15", "2024.03.15.2", "2024.03.15.10"},
+		},
+		{
+			spec:     Spec{Scheme: "calver"},
+			versions: []string{"20240315-abcdef", "20231201-123456"},
+			expected: []string{"20231201-123456", "20240315-abcdef"},
+		},
+		{
+			spec:     Spec{Scheme: "loose"},
+			versions: []string{"3.12-alpine3.19", "3.12-alpine3.9", "3.9-alpine3.19", "3.12"},
+			expected: []string{"3.9-alpine3.19", "3.12", "3.12-alpine3.9", "3.12-alpine3.19"},
+		},
+		{
+			spec:     Spec{Scheme: "pep440"},
+			versions: []string{"1.0.post1", "1.0", "1.0rc1", "1.0.dev1", "1.0a2", "1.0b1", "1.0a1.dev1", "1!0.1", "1.0.1", "1.0+local.2", "1.0+local.10", "0.9.9.9"},
+			expected: []string{"0.9.9.9", "1.0.dev1", "1.0a1.dev1", "1.0a2", "1.0b1", "1.0rc1", "1.0", "1.0+local.2", "1.0+local.10", "1.0.post1", "1.0.1", "1!0.1"},
+		},
+		{
+			spec:     Spec{Scheme: "maven"},
+			versions: []string{"1-123", "1-2", "1-1", "1-1-snapshot", "1-pom-1", "1-def", "1-abc", "1-sp123", "1-sp2", "1-sp", "1", "1-SNAPSHOT", "1-rc123", "1-cr2", "1-rc", "1-m11", "1-m2", "1-beta123", "1-beta-2", "1-alpha-123", "1-alpha2", "1-alpha2snapshot"},
+			expected: []string{"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2", "1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot", "1-1", "1-2", "1-123"},
+		},
+		{
+			spec:     Spec{Scheme: "maven"},
+			versions: []string{"11m", "11c", "11b", "11.a", "11", "11.m11", "11.m2", "11.b11", "11.b2", "11.a11", "11.a2", "2.123", "2.2", "2.1.0.1", "2.1-1", "2.1-c", "2.1b", "2.1-a", "2.1.0", "2.0.123", "2.0.2", "2.0.0.a", "2.0.a", "2-1", "2.0"},
+			expected: []string{"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1", "2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m"},
+		},
+		{
+			spec:     Spec{Scheme: "maven"},
+			versions: []string{"2.0.0", "2.0.0-RC1", "1.32.0", "2.0.1.Final", "1.9.0", "2.0.0-M1"},
+			expected: []string{"1.9.0", "1.32.0", "2.0.0-M1", "2.0.0-RC1", "2.0.0", "2.0.1.Final"},
+		},
+		{
+			spec: Spec{
+				Scheme:  "regex",
+				Pattern: `^(?P<major>\d+)\.(?P<minor>\d+)-alpine(?P<alpine>[\d.]+)$`,
+				SortBy:  []string{"alpine", "major", "minor"},
+			},
+			versions: []string{"3.12-alpine3.19", "3.13-alpine3.18", "3.11-alpine3.19"},
+			expected: []string{"3.13-alpine3.18", "3.11-alpine3.19", "3.12-alpine3.19"},
+		},
+	}
+
+	for i, tc := range testcases {
+		s, err := New(tc.spec)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		actual := append([]string{}, tc.versions...)
+
+		sort.SliceStable(actual, func(i, j int) bool {
+			c, err := s.Compare(actual[i], actual[j])
+			if err != nil {
+				t.Fatalf("#%d: %v", i, err)
+			}
+			return c < 0
+		})
+
+		if d := cmp.Diff(tc.expected, actual); d != "" {
+			t.Errorf("#%d: unexpected order: %s", i, d)
+		}
+	}
+}
+
+func TestSchemes_Parse(t *testing.T) {
+	regex, err := NewRegex(`^(?P<major>\d+)\.(?P<minor>\d+)-alpine`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		scheme   Scheme
+		version  string
+		expected string
+	}{
+		{scheme: Calver, version: "2024.03.15", expected: "2024.3.15"},
+		{scheme: Calver, version: "20240315-abcdef", expected: "2024.3.15"},
+		{scheme: Loose, version: "3.12-alpine3.19", expected: "3.12.0"},
+		{scheme: regex, version: "3.12-alpine3.19", expected: "3.12.0"},
+		{scheme: PEP440, version: "2.15", expected: "2.15.0"},
+		{scheme: PEP440, version: "2.0.0RC1", expected: "2.0.0-rc.1"},
+		{scheme: PEP440, version: "1.0.dev3", expected: "1.0.0-dev.3"},
+		{scheme: PEP440, version: "1.32.0.post1", expected: "1.32.0"},
+		{scheme: Maven, version: "2.0.0-RC1", expected: "2.0.0-rc.1"},
+		{scheme: Maven, version: "2.0.0-M1", expected: "2.0.0-milestone.1"},
+		{scheme: Maven, version: "5.3.20.RELEASE", expected: "5.3.20"},
+		{scheme: Maven, version: "33.0.0-jre", expected: "33.0.0"},
+		{scheme: Maven, version: "1.2.3.4", expected: "1.2.3"},
+		{scheme: Maven, version: "1.0", expected: "1.0.0"},
+	}
+
+	for i, tc := range testcases {
+		v, err := tc.scheme.Parse(tc.version)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		if v.String() != tc.expected {
+			t.Errorf("#%d: expected %s, got %s", i, tc.expected, v.String())
+		}
+	}
+
+	for _, invalid := range []string{"1.2.3", "2024.13.01", "latest"} {
+		if _, err := Calver.Parse(invalid); err == nil {
+			t.Errorf("expected error for calver %q", invalid)
+		}
+	}
+
+	normalized := map[string]string{
+		"1.0-RC.1":          "1.0rc1",
+		"v1.0alpha":         "1.0a0",
+		"1.0-1":             "1.0.post1",
+		"1.0.0_preview2":    "1.0.0rc2",
+		"0!1.0.r3-dev.4+Ub": "1.0.post3.dev4+ub",
+	}
+	for v, expected := range normalized {
+		actual, err := NormalizePEP440(v)
+		if err != nil {
+			t.Errorf("%s: %v", v, err)
+		} else if actual != expected {
+			t.Errorf("unexpected normalization of %s: expected=%s, got=%s", v, expected, actual)
+		}
+	}
+
+	if _, err := PEP440.Parse("1.0-final"); err == nil {
+		t.Error("expected error for an invalid PEP 440 version")
+	}
+
+	if _, err := regex.Parse("latest"); err == nil {
+		t.Error("expected error for a version not matching the pattern")
+	}
+
+	if _, err := New(Spec{Scheme: "regex", Pattern: `^\d+$`}); err == nil {
+		t.Error("expected error for a pattern without named groups")
+	}
+
+	if _, err := New(Spec{Scheme: "regex", Pattern: `^(?P<major>\d+)$`, SortBy: []string{"minor"}}); err == nil {
+		t.Error("expected error for sorting by an unknown group")
+	}
+}
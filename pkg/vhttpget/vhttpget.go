@@ -6,6 +6,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type Option interface {
@@ -22,18 +24,48 @@ func (o Opts) Set(another *Opts) {
 
 type Getter interface {
 	DoRequest(url string, opt ...Option) (string, error)
+	// Get is the same as DoRequest, except that it returns the response headers along with the body
+	Get(url string, opt ...Option) (*Response, error)
+}
+
+type Response struct {
+	URL    string
+	Header http.Header
+	Body   string
+}
+
+// StatusError is returned when the server responded with a non-2xx status code
+type StatusError struct {
+	URL        string
+	Status     string
+	StatusCode int
+	// Body is the beginning of the response body, for diagnostics
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Body) > 0 {
+		return fmt.Sprintf("GET %s: %s: %s", e.URL, e.Status, e.Body)
+	}
+	return fmt.Sprintf("GET %s: %s", e.URL, e.Status)
 }
 
 type getter struct {
-	responseBodyFor func(url string, opts Opts) (io.ReadCloser, error)
+	responseBodyFor func(url string, opts Opts) (io.ReadCloser, http.Header, error)
 }
 
 func New() Getter {
+	return NewWithClient(http.DefaultClient)
+}
+
+// NewWithClient returns a Getter that sends requests with the given client.
+// This is useful for testing with self-signed certificates.
+func NewWithClient(client *http.Client) Getter {
 	return &getter{
-		responseBodyFor: func(url string, opts Opts) (io.ReadCloser, error) {
+		responseBodyFor: func(url string, opts Opts) (io.ReadCloser, http.Header, error) {
 			req, err := http.NewRequest(http.MethodGet, url, &bytes.Buffer{})
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			if header := opts.Header; header != nil {
@@ -42,54 +74,104 @@ func New() Getter {
 				}
 			}
 
-			res, err := http.DefaultClient.Do(req)
+			res, err := client.Do(req)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			if res.StatusCode < 200 || res.StatusCode >= 300 {
 				defer res.Body.Close()
 				body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
-				snippet := string(body)
-				if len(snippet) > 0 {
-					return nil, fmt.Errorf("GET %s: %s: %s", url, res.Status, snippet)
-				}
-				return nil, fmt.Errorf("GET %s: %s", url, res.Status)
+				return nil, nil, &StatusError{URL: url, Status: res.Status, StatusCode: res.StatusCode, Body: string(body)}
 			}
 
-			return res.Body, nil
+			return res.Body, res.Header, nil
 		},
 	}
 }
 
 func NewTester(expectations map[string]string) Getter {
 	return &getter{
-		responseBodyFor: func(url string, opts Opts) (io.ReadCloser, error) {
+		responseBodyFor: func(url string, opts Opts) (io.ReadCloser, http.Header, error) {
 			res, ok := expectations[url]
 			if !ok {
-				return nil, fmt.Errorf("unexpected input: url=%v, opts=%v", url, opts)
+				return nil, nil, fmt.Errorf("unexpected input: url=%v, opts=%v", url, opts)
 			}
 			r := ioutil.NopCloser(bytes.NewReader([]byte(res)))
-			return r, nil
+			return r, http.Header{}, nil
 		},
 	}
 }
 
 func (t *getter) DoRequest(url string, opt ...Option) (string, error) {
+	res, err := t.Get(url, opt...)
+	if err != nil {
+		return "", err
+	}
+
+	return res.Body, nil
+}
+
+func (t *getter) Get(url string, opt ...Option) (*Response, error) {
 	opts := &Opts{}
 	for _, o := range opt {
 		o.Set(opts)
 	}
 
-	res, err := t.responseBodyFor(url, *opts)
+	body, header, err := t.responseBodyFor(url, *opts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer body.Close()
 
-	bytes, err := ioutil.ReadAll(res)
+	bytes, err := ioutil.ReadAll(body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return &Response{URL: url, Header: header, Body: string(bytes)}, nil
+}
+
+// NextLink returns the URL of the next page found in the RFC 5988 Link header of the response, like GitHub API returns:
+//
+//	Link: <https://api.github.com/repositories/1/releases?page=2>; rel="next", <https://api.github.com/repositories/1/releases?page=5>; rel="last"
+//
+// A relative URL is resolved against the URL of the request. It returns an empty string when there is no next page.
+func (r *Response) NextLink() (string, error) {
+	for _, header := range r.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			segments := strings.Split(link, ";")
+			if len(segments) < 2 {
+				continue
+			}
+
+			var isNext bool
+			for _, param := range segments[1:] {
+				kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(kv) == 2 && kv[0] == "rel" {
+					for _, rel := range strings.Fields(strings.Trim(kv[1], `"`)) {
+						isNext = isNext || rel == "next"
+					}
+				}
+			}
+
+			if !isNext {
+				continue
+			}
+
+			next, err := url.Parse(strings.Trim(strings.TrimSpace(segments[0]), "<>"))
+			if err != nil {
+				return "", fmt.Errorf("invalid next link in %q: %w", header, err)
+			}
+
+			cur, err := url.Parse(r.URL)
+			if err != nil {
+				return "", err
+			}
+
+			return cur.ResolveReference(next).String(), nil
+		}
 	}
 
-	return string(bytes), nil
+	return "", nil
 }